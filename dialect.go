@@ -0,0 +1,91 @@
+package json5
+
+import "strings"
+
+// Dialect is a set of syntax extensions accepted on top of strict JSON.
+// The zero value accepts full JSON5; individual extensions can be turned
+// on or off by combining the Allow* flags with one of the predefined
+// dialects, e.g. JSON5 &^ AllowHexNumbers or JSON | AllowComments.
+type Dialect uint
+
+// Syntax extensions
+const (
+	// AllowComments accepts // and /* */ comments
+	AllowComments Dialect = 1 << iota
+	// AllowTrailingCommas accepts a comma after the last array element or
+	// object member
+	AllowTrailingCommas
+	// AllowUnquotedKeys accepts identifiers as property names
+	AllowUnquotedKeys
+	// AllowSingleQuotes accepts strings delimited by '
+	AllowSingleQuotes
+	// AllowHexNumbers accepts hexadecimal integers such as 0x1F
+	AllowHexNumbers
+	// AllowInfinityNaN accepts Infinity, -Infinity and NaN values
+	AllowInfinityNaN
+	// AllowLeadingPlus accepts an explicit plus sign on numbers
+	AllowLeadingPlus
+	// AllowMultilineStrings accepts escaped line breaks in strings
+	AllowMultilineStrings
+	// AllowRelaxedDecimals accepts numbers with a leading or trailing
+	// decimal point such as .5 and 5.
+	AllowRelaxedDecimals
+	// AllowExtraEscapes accepts the JSON5 escapes \', \v, \0, \xHH and any
+	// escaped character that stands for itself
+	AllowExtraEscapes
+	// AllowExtraWhitespace accepts \v, \f and Unicode space separators
+	// between tokens
+	AllowExtraWhitespace
+
+	// dialectStrict tells strict JSON apart from the zero value
+	dialectStrict
+)
+
+// Predefined dialects
+const (
+	// JSON is strict RFC 8259 JSON
+	JSON = dialectStrict
+	// JSONC is JSON with comments and trailing commas, as used by VS Code
+	JSONC = JSON | AllowComments | AllowTrailingCommas
+	// JSON5 is the full JSON5 syntax
+	JSON5 = JSONC | AllowUnquotedKeys | AllowSingleQuotes | AllowHexNumbers |
+		AllowInfinityNaN | AllowLeadingPlus | AllowMultilineStrings |
+		AllowRelaxedDecimals | AllowExtraEscapes | AllowExtraWhitespace
+)
+
+var extensionNames = map[Dialect]string{
+	AllowComments:         "comments",
+	AllowTrailingCommas:   "trailing commas",
+	AllowUnquotedKeys:     "unquoted keys",
+	AllowSingleQuotes:     "single-quoted strings",
+	AllowHexNumbers:       "hexadecimal numbers",
+	AllowInfinityNaN:      "Infinity and NaN",
+	AllowLeadingPlus:      "leading plus signs",
+	AllowMultilineStrings: "multi-line strings",
+	AllowRelaxedDecimals:  "leading or trailing decimal points",
+	AllowExtraEscapes:     "JSON5 escape sequences",
+	AllowExtraWhitespace:  "JSON5 whitespace characters",
+}
+
+// Allows reports whether all extensions in ext are accepted
+func (d Dialect) Allows(ext Dialect) bool {
+	return d == 0 || d&ext == ext
+}
+
+func (d Dialect) String() string {
+	switch d {
+	case 0, JSON5:
+		return "JSON5"
+	case JSONC:
+		return "JSONC"
+	case JSON:
+		return "strict JSON"
+	}
+	var names []string
+	for ext := AllowComments; ext < dialectStrict; ext <<= 1 {
+		if d&ext != 0 {
+			names = append(names, extensionNames[ext])
+		}
+	}
+	return "JSON with " + strings.Join(names, ", ")
+}
@@ -1,25 +1,304 @@
 package json5
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrorKind classifies a SyntaxError
+type ErrorKind int
+
+// Syntax error kinds
+const (
+	// KindUnexpectedCharacter is a character that cannot start or continue
+	// a token
+	KindUnexpectedCharacter ErrorKind = iota
+	// KindUnexpectedToken is a well-formed token in the wrong place
+	KindUnexpectedToken
+	// KindUnexpectedEOF is input that ends in the middle of a value
+	KindUnexpectedEOF
+	// KindInvalidEscape is a malformed escape sequence
+	KindInvalidEscape
+	// KindInvalidUTF8 is a byte sequence that is not valid UTF-8
+	KindInvalidUTF8
+	// KindControlCharacter is a raw control character inside a string
+	KindControlCharacter
+	// KindDisallowedExtension is syntax the selected Dialect does not allow
+	KindDisallowedExtension
+	// KindNumberOutOfRange is a number that cannot be represented
+	KindNumberOutOfRange
+	// KindDuplicateKey is a property name that appears twice in an object
+	KindDuplicateKey
+	// KindDepthExceeded is nesting deeper than the parser allows
+	KindDepthExceeded
+	// KindInputTooLarge is input longer than the parser allows
+	KindInputTooLarge
+	// KindStringTooLong is a string or identifier longer than the lexer
+	// allows
+	KindStringTooLong
+	// KindNumberTooLong is a number literal longer than the lexer allows
+	KindNumberTooLong
+	// KindTooManyElements is an array or object with more members than the
+	// parser allows
+	KindTooManyElements
+	// KindTooManyKeys is input with more property names than the parser
+	// allows
+	KindTooManyKeys
+)
+
+// Sentinel errors matching each ErrorKind with errors.Is
+var (
+	ErrUnexpectedCharacter = errors.New("json5: unexpected character")
+	ErrUnexpectedToken     = errors.New("json5: unexpected token")
+	ErrUnexpectedEOF       = errors.New("json5: unexpected end of JSON")
+	ErrInvalidEscape       = errors.New("json5: invalid escape sequence")
+	ErrInvalidUTF8         = errors.New("json5: invalid UTF-8")
+	ErrControlCharacter    = errors.New("json5: control character in string")
+	ErrDisallowedExtension = errors.New("json5: extension not allowed in dialect")
+	ErrNumberOutOfRange    = errors.New("json5: number out of range")
+	ErrDuplicateKey        = errors.New("json5: duplicate key")
+	ErrDepthExceeded       = errors.New("json5: nesting depth exceeded")
+	ErrInputTooLarge       = errors.New("json5: input too large")
+	ErrStringTooLong       = errors.New("json5: string too long")
+	ErrNumberTooLong       = errors.New("json5: number literal too long")
+	ErrTooManyElements     = errors.New("json5: too many elements")
+	ErrTooManyKeys         = errors.New("json5: too many keys")
+)
+
+var kindErrors = [...]error{
+	KindUnexpectedCharacter: ErrUnexpectedCharacter,
+	KindUnexpectedToken:     ErrUnexpectedToken,
+	KindUnexpectedEOF:       ErrUnexpectedEOF,
+	KindInvalidEscape:       ErrInvalidEscape,
+	KindInvalidUTF8:         ErrInvalidUTF8,
+	KindControlCharacter:    ErrControlCharacter,
+	KindDisallowedExtension: ErrDisallowedExtension,
+	KindNumberOutOfRange:    ErrNumberOutOfRange,
+	KindDuplicateKey:        ErrDuplicateKey,
+	KindDepthExceeded:       ErrDepthExceeded,
+	KindInputTooLarge:       ErrInputTooLarge,
+	KindStringTooLong:       ErrStringTooLong,
+	KindNumberTooLong:       ErrNumberTooLong,
+	KindTooManyElements:     ErrTooManyElements,
+	KindTooManyKeys:         ErrTooManyKeys,
+}
+
+var errorKindNames = [...]string{
+	KindUnexpectedCharacter: "unexpected_character",
+	KindUnexpectedToken:     "unexpected_token",
+	KindUnexpectedEOF:       "unexpected_eof",
+	KindInvalidEscape:       "invalid_escape",
+	KindInvalidUTF8:         "invalid_utf8",
+	KindControlCharacter:    "control_character",
+	KindDisallowedExtension: "disallowed_extension",
+	KindNumberOutOfRange:    "number_out_of_range",
+	KindDuplicateKey:        "duplicate_key",
+	KindDepthExceeded:       "depth_exceeded",
+	KindInputTooLarge:       "input_too_large",
+	KindStringTooLong:       "string_too_long",
+	KindNumberTooLong:       "number_too_long",
+	KindTooManyElements:     "too_many_elements",
+	KindTooManyKeys:         "too_many_keys",
+}
+
+func (k ErrorKind) String() string {
+	if k < 0 || int(k) >= len(errorKindNames) {
+		return fmt.Sprintf("ErrorKind(%d)", int(k))
+	}
+	return errorKindNames[k]
+}
 
 // SyntaxError means JSON has an incorrect syntax
 type SyntaxError struct {
+	Kind ErrorKind
+	// Offset is the byte offset of the error in the source
+	Offset int
+	// Line and Column locate Offset, both 1-based. Columns count runes.
+	Line   int
+	Column int
+
 	message string
-	index   int
+	err     error
 }
 
 func (e *SyntaxError) Error() string {
-	return fmt.Sprintf("json5: %s at position %d", e.message, e.index)
+	return fmt.Sprintf("json5: %s at position %d", e.message, e.Offset)
+}
+
+// Is reports whether target is the sentinel error of the error kind
+func (e *SyntaxError) Is(target error) bool {
+	return int(e.Kind) < len(kindErrors) && kindErrors[e.Kind] == target
+}
+
+// Unwrap returns the underlying error, such as a *strconv.NumError
+func (e *SyntaxError) Unwrap() error {
+	return e.err
+}
+
+// SyntaxErrors lists the errors found by a Parser in recovery mode, in
+// source order
+type SyntaxErrors []*SyntaxError
+
+func (es SyntaxErrors) Error() string {
+	switch len(es) {
+	case 0:
+		return "json5: no errors"
+	case 1:
+		return es[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", es[0].Error(), len(es)-1)
 }
 
-func badCharError(ch byte, index int) *SyntaxError {
-	return &SyntaxError{fmt.Sprintf("unexpected character: %c", ch), index}
+// Unwrap returns the individual errors for errors.Is and errors.As
+func (es SyntaxErrors) Unwrap() []error {
+	errs := make([]error, len(es))
+	for i, e := range es {
+		errs[i] = e
+	}
+	return errs
+}
+
+// Excerpt renders the error for humans: a name:line:column: message header
+// followed by the offending source line and a caret under the error column.
+// The name, typically a file name, is omitted from the header when empty.
+func (e *SyntaxError) Excerpt(name string, src []byte) string {
+	var sb strings.Builder
+	if name != "" {
+		sb.WriteString(name)
+		sb.WriteByte(':')
+	}
+	fmt.Fprintf(&sb, "%d:%d: %s\n", e.Line, e.Column, e.message)
+	offset := e.Offset
+	if offset > len(src) {
+		offset = len(src)
+	}
+	begin, end := offset, offset
+	for begin > 0 {
+		r, size := utf8.DecodeLastRune(src[:begin])
+		if isLineTerminator(r) {
+			break
+		}
+		begin -= size
+	}
+	for end < len(src) {
+		r, size := utf8.DecodeRune(src[end:])
+		if isLineTerminator(r) {
+			break
+		}
+		end += size
+	}
+	sb.Write(src[begin:end])
+	sb.WriteByte('\n')
+	// keep tabs so that the caret lines up with the source line
+	for _, r := range string(src[begin:offset]) {
+		if r == '\t' {
+			sb.WriteByte('\t')
+		} else {
+			sb.WriteByte(' ')
+		}
+	}
+	sb.WriteByte('^')
+	return sb.String()
+}
+
+// locate fills in the line and column of the error offset in str
+func (e *SyntaxError) locate(str []byte) {
+	var lc lineCounter
+	offset := e.Offset
+	if offset > len(str) {
+		offset = len(str)
+	}
+	lc.Advance(str, offset)
+	e.Line = lc.line + 1
+	e.Column = lc.column + 1
+}
+
+func newSyntaxError(kind ErrorKind, message string, index int) *SyntaxError {
+	return &SyntaxError{Kind: kind, Offset: index, message: message}
+}
+
+// badCharError quotes the whole character at index, which may span several
+// bytes. Control characters are given by code point, and a byte that does
+// not start a valid UTF-8 sequence is reported as such.
+func badCharError(str []byte, index int) *SyntaxError {
+	r, size := utf8.DecodeRune(str[index:])
+	if r == utf8.RuneError && size <= 1 {
+		return badUTF8Error(str[index], index)
+	}
+	if unicode.IsControl(r) {
+		return newSyntaxError(KindUnexpectedCharacter, fmt.Sprintf("unexpected character: U+%04X", r), index)
+	}
+	return newSyntaxError(KindUnexpectedCharacter, fmt.Sprintf("unexpected character: %c", r), index)
+}
+
+func badControlCharError(ch byte, index int) *SyntaxError {
+	return newSyntaxError(KindControlCharacter, fmt.Sprintf("invalid control character in string: U+%04X", ch), index)
+}
+
+func badUTF8Error(ch byte, index int) *SyntaxError {
+	return newSyntaxError(KindInvalidUTF8, fmt.Sprintf("invalid UTF-8 byte: %#02x", ch), index)
 }
 
 func badTokenError(token string, index int) *SyntaxError {
-	return &SyntaxError{fmt.Sprintf("unexpected token: %s", token), index}
+	return newSyntaxError(KindUnexpectedToken, fmt.Sprintf("unexpected token: %s", token), index)
+}
+
+func unexpectedTokenError(source, expected string, index int) *SyntaxError {
+	return newSyntaxError(KindUnexpectedToken, fmt.Sprintf("unexpected token: %s, expected %s", source, expected), index)
+}
+
+func unexpectedEOFError(expected string, index int) *SyntaxError {
+	return newSyntaxError(KindUnexpectedEOF, "unexpected end of JSON, expected "+expected, index)
+}
+
+func badEscapeError(seq string, index int) *SyntaxError {
+	return newSyntaxError(KindInvalidEscape, fmt.Sprintf("invalid escape sequence: %s", seq), index)
+}
+
+// badHexDigitError reports a bad digit at index in the escape sequence that
+// begins at start, or the unterminated sequence at the end of input
+func badHexDigitError(str []byte, index, start int) *SyntaxError {
+	if index >= len(str) {
+		return unterminatedError("escape sequence", start)
+	}
+	return newSyntaxError(KindInvalidEscape, fmt.Sprintf("invalid hexadecimal digit: %c", str[index]), index)
+}
+
+func leadingCommaError(index int) *SyntaxError {
+	return newSyntaxError(KindUnexpectedToken, "unexpected leading comma", index)
+}
+
+func extraCommaError(index int) *SyntaxError {
+	return newSyntaxError(KindUnexpectedToken, "unexpected extra comma", index)
+}
+
+func badExtensionError(ext Dialect, d Dialect, index int) *SyntaxError {
+	return newSyntaxError(KindDisallowedExtension, fmt.Sprintf("%s are not allowed in %s", extensionNames[ext], d), index)
+}
+
+func duplicateKeyError(name, path string, first, index int) *SyntaxError {
+	return newSyntaxError(KindDuplicateKey, fmt.Sprintf("duplicate key %q in object %s (previous at position %d)", name, path, first), index)
+}
+
+func badNumberError(source string, err error, index int) *SyntaxError {
+	e := newSyntaxError(KindNumberOutOfRange, fmt.Sprintf("number out of range: %s", source), index)
+	e.err = err
+	return e
+}
+
+func limitError(kind ErrorKind, what string, limit, index int) *SyntaxError {
+	return newSyntaxError(kind, fmt.Sprintf("%s exceeds the limit of %d", what, limit), index)
+}
+
+// isLimitError reports whether err is a resource limit being exceeded
+func isLimitError(err error) bool {
+	e, ok := err.(*SyntaxError)
+	return ok && e.Kind >= KindDepthExceeded && e.Kind <= KindTooManyKeys
 }
 
-func badEOF(index int) *SyntaxError {
-	return &SyntaxError{"unexpected end of JSON", index}
+func unterminatedError(construct string, index int) *SyntaxError {
+	return newSyntaxError(KindUnexpectedEOF, "unexpected end of JSON: unterminated "+construct, index)
 }
@@ -0,0 +1,103 @@
+package json5_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	json5 "github.com/goasm/gojson5"
+)
+
+func expectSyntaxError(t *testing.T, err error) *json5.SyntaxError {
+	t.Helper()
+	e, ok := err.(*json5.SyntaxError)
+	if !ok {
+		t.Fatal("Expected a SyntaxError:", err)
+	}
+	return e
+}
+
+func TestSyntaxErrorPosition(t *testing.T) {
+	src := []byte("{\n  \"foo\": 1,\r\n  \"bär\": 'x\\1'\n}")
+	parser := json5.Parser{}
+	_, err := parser.Parse(src)
+	e := expectSyntaxError(t, err)
+	equals(t, json5.KindInvalidEscape, e.Kind)
+	equals(t, 27, e.Offset)
+	equals(t, 3, e.Line)
+	equals(t, 12, e.Column)
+}
+
+func TestSyntaxErrorKinds(t *testing.T) {
+	samples := []string{
+		`[1 }`, `@`, `"abc`, `"\x4G"`, "'\xff'", "'\n'", `// c`,
+	}
+	expectedKinds := []json5.ErrorKind{
+		json5.KindUnexpectedToken,
+		json5.KindUnexpectedCharacter,
+		json5.KindUnexpectedEOF,
+		json5.KindInvalidEscape,
+		json5.KindInvalidUTF8,
+		json5.KindControlCharacter,
+		json5.KindDisallowedExtension,
+	}
+	for idx, sample := range samples {
+		parser := json5.Parser{}
+		if idx == len(samples)-1 {
+			parser.Dialect = json5.JSON
+		}
+		_, err := parser.Parse([]byte(sample))
+		e := expectSyntaxError(t, err)
+		equals(t, expectedKinds[idx], e.Kind)
+	}
+	equals(t, "unexpected_eof", json5.KindUnexpectedEOF.String())
+}
+
+func TestSyntaxErrorExcerpt(t *testing.T) {
+	src := []byte("{\n\t\"foo\": {\n\t\t\"bär\": @ }\n}")
+	parser := json5.Parser{}
+	_, err := parser.Parse(src)
+	e := expectSyntaxError(t, err)
+	equals(t, 3, e.Line)
+	equals(t, 10, e.Column)
+	expected := "config.json5:3:10: unexpected character: @\n" +
+		"\t\t\"bär\": @ }\n" +
+		"\t\t       ^"
+	equals(t, expected, e.Excerpt("config.json5", src))
+}
+
+func TestSyntaxErrorIs(t *testing.T) {
+	samples := []string{
+		`[1 }`, `@`, `"abc`, `"\x4G"`, "'\xff'", "'\n'", `99999999999999999999`, `1e400`,
+	}
+	expectedErrors := []error{
+		json5.ErrUnexpectedToken,
+		json5.ErrUnexpectedCharacter,
+		json5.ErrUnexpectedEOF,
+		json5.ErrInvalidEscape,
+		json5.ErrInvalidUTF8,
+		json5.ErrControlCharacter,
+		json5.ErrNumberOutOfRange,
+		json5.ErrNumberOutOfRange,
+	}
+	for idx, sample := range samples {
+		parser := json5.Parser{}
+		_, err := parser.Parse([]byte(sample))
+		equals(t, true, errors.Is(err, expectedErrors[idx]))
+		equals(t, false, errors.Is(err, json5.ErrDuplicateKey))
+		var e *json5.SyntaxError
+		equals(t, true, errors.As(err, &e))
+	}
+}
+
+func TestSyntaxErrorUnwrap(t *testing.T) {
+	parser := json5.Parser{}
+	_, err := parser.Parse([]byte(`[1, 0x1FFFFFFFFFFFFFFFF]`))
+	equals(t, true, errors.Is(err, json5.ErrNumberOutOfRange))
+	equals(t, true, errors.Is(err, strconv.ErrRange))
+	var numErr *strconv.NumError
+	equals(t, true, errors.As(err, &numErr))
+	e := expectSyntaxError(t, err)
+	equals(t, 4, e.Offset)
+	equals(t, "number_out_of_range", e.Kind.String())
+}
@@ -0,0 +1,69 @@
+package json5
+
+import "sync"
+
+var parserPool = sync.Pool{
+	New: func() interface{} {
+		return new(Parser)
+	},
+}
+
+// Buffers and stacks that grew beyond these sizes are not pooled, so that a
+// single huge or deeply nested input does not pin its memory
+const (
+	maxPooledBuffer = 64 << 10
+	maxPooledStack  = 1 << 10
+)
+
+// putParser returns a parser to the pool without references to its last
+// input and result
+func putParser(p *Parser) {
+	p.validate = false
+	p.reset(nil)
+	if p.buf.Cap() > maxPooledBuffer {
+		p.buf = stringBuffer{}
+	}
+	if cap(p.stage.elements) > maxPooledStack {
+		p.stage.elements = nil
+	}
+	if cap(p.paths.elements) > maxPooledStack {
+		p.paths.elements = nil
+	}
+	if cap(p.stack.elements) > maxPooledStack {
+		p.stack.elements = nil
+	}
+	if cap(p.frames) > maxPooledStack {
+		p.frames = nil
+	}
+	parserPool.Put(p)
+}
+
+// Parse parses the JSON5 bytes with a pooled Parser using the default options
+func Parse(data []byte) (interface{}, error) {
+	p := parserPool.Get().(*Parser)
+	value, err := p.Parse(data)
+	putParser(p)
+	return value, err
+}
+
+// ParseString parses the JSON5 string like Parse
+func ParseString(s string) (interface{}, error) {
+	return Parse([]byte(s))
+}
+
+// Valid reports whether data is a well-formed JSON5 value
+func Valid(data []byte) bool {
+	return Validate(data) == nil
+}
+
+// Validate checks that data is a well-formed JSON5 value and returns the
+// first syntax error otherwise, as a *SyntaxError. Unlike Parse it builds no
+// values and decodes no strings, so valid input is checked without
+// allocating.
+func Validate(data []byte) error {
+	p := parserPool.Get().(*Parser)
+	p.validate = true
+	_, err := p.Parse(data)
+	putParser(p)
+	return err
+}
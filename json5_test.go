@@ -3,6 +3,8 @@ package json5_test
 import (
 	"strings"
 	"testing"
+
+	json5 "github.com/goasm/gojson5"
 )
 
 func noError(t *testing.T, err error) {
@@ -32,3 +34,44 @@ func equals(t *testing.T, expected, actual interface{}) {
 
 func TestDecoding(t *testing.T) {
 }
+
+func TestParse(t *testing.T) {
+	raw, err := json5.Parse([]byte(`{foo: [1, 2], bar: 'baz',}`))
+	noError(t, err)
+	val, ok := raw.(map[string]interface{})
+	equals(t, true, ok)
+	equals(t, 2, len(val))
+	equals(t, "baz", val["bar"])
+	_, err = json5.Parse([]byte(`[1, 2`))
+	hasError(t, err, "unexpected end of JSON")
+	raw, err = json5.ParseString(`'foo'`)
+	noError(t, err)
+	equals(t, "foo", raw)
+}
+
+func TestParseAfterHugeInput(t *testing.T) {
+	deep := strings.Repeat("[", 5000) + strings.Repeat("]", 5000)
+	_, err := json5.ParseString(deep)
+	noError(t, err)
+	_, err = json5.ParseString(`"` + strings.Repeat("x", 100<<10) + `"`)
+	noError(t, err)
+	raw, err := json5.ParseString(`{a: [1, 2]}`)
+	noError(t, err)
+	equals(t, 2, len(raw.(map[string]interface{})["a"].([]interface{})))
+}
+
+func TestParseConcurrently(t *testing.T) {
+	done := make(chan error)
+	for i := 0; i < 8; i++ {
+		go func() {
+			var err error
+			for j := 0; j < 100 && err == nil; j++ {
+				_, err = json5.ParseString(`{"a": [1, {"b": null}], c: 0x10}`)
+			}
+			done <- err
+		}()
+	}
+	for i := 0; i < 8; i++ {
+		noError(t, <-done)
+	}
+}
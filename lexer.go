@@ -1,5 +1,11 @@
 package json5
 
+import (
+	"bytes"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
 // TokenType represents an enum of token types
 type TokenType int
 type lexerState int
@@ -15,10 +21,14 @@ const (
 	TypePairSep
 	TypeString
 	TypeInteger
+	TypeHexInteger
 	TypeFloat
+	TypeInfinity
+	TypeNaN
 	TypeFalse
 	TypeTrue
 	TypeNull
+	TypeIdentifier
 	TypeEOF
 )
 
@@ -36,25 +46,75 @@ const (
 	stateUnsignedNumber
 	stateZero
 	stateDecimalInteger
+	stateHexPrefix
+	stateHexInteger
+	stateLeadingPoint
 	statePoint
 	stateDecimalFraction
 	stateDecimalExponent
 	stateUnsignedDecimalExponent
-	stateLiteral
+	stateDecimalExponentInteger
+	stateIdentifier
+	stateIdentifierPart
+	stateIdentifierEscape
+)
+
+// UTF8Policy controls how a Lexer treats bytes that are not valid UTF-8
+type UTF8Policy int
+
+// Invalid UTF-8 policies
+const (
+	// UTF8Reject fails with a SyntaxError at the first invalid byte
+	UTF8Reject UTF8Policy = iota
+	// UTF8Replace decodes each invalid byte in a string as U+FFFD
+	UTF8Replace
+	// UTF8Pass copies invalid bytes into strings unchanged
+	UTF8Pass
 )
 
 // Token represents an unit for syntax analysis
 type Token struct {
 	Type TokenType
 	Raw  string
+	// Start and End are the byte offsets of the token in the source, End
+	// being exclusive
+	Start int
+	End   int
+	// Line and Column locate Start, both 1-based. Columns count runes.
+	Line   int
+	Column int
 }
 
-// Lexer reads and tokenizes a JSON string
+// Lexer reads and tokenizes a JSON string.
+//
+// A \uXXXX escape of a high surrogate immediately followed by an escaped low
+// surrogate decodes to the code point of the pair. A surrogate escape
+// without its partner is not an error: it decodes to U+FFFD, the Unicode
+// replacement character, and the character after it is read normally.
 type Lexer struct {
+	// Dialect selects the accepted syntax extensions, full JSON5 by default
+	Dialect Dialect
+	// InvalidUTF8 selects how invalid UTF-8 in strings and comments is
+	// handled. Identifiers and the space between tokens must always be
+	// valid UTF-8.
+	InvalidUTF8 UTF8Policy
+	// AllowControlChars accepts raw U+0000 to U+001F characters, including
+	// line breaks, inside strings. They must be escaped by default.
+	AllowControlChars bool
+	// MaxStringLength limits the source bytes of a string between the
+	// quotes, or of an identifier. Zero means no limit.
+	MaxStringLength int
+	// MaxNumberLength limits the source bytes of a number literal, sign
+	// included. Zero means no limit.
+	MaxNumberLength int
+
 	str   []byte
 	pos   int
+	start int
+	quote byte
 	state lexerState
 	buf   stringBuffer
+	lines lineCounter
 	ps    *Parser
 }
 
@@ -63,20 +123,40 @@ func Scan(s string) *Lexer {
 	return &Lexer{str: []byte(s)}
 }
 
-func (l *Lexer) readDefault(c byte) (tk Token, err error) {
+func (l *Lexer) readDefault(c byte) (typ TokenType, err error) {
 	switch c {
 	case ' ', '\t', '\n', '\r':
 		l.pos++
 	case '/':
+		if !l.Dialect.Allows(AllowComments) {
+			err = badExtensionError(AllowComments, l.Dialect, l.pos)
+			return
+		}
 		l.state = stateComment
+		l.start = l.pos
 		l.pos++
 	default:
+		r, size := decodeRune(l, c)
+		if r == '\uFEFF' && l.pos == 0 {
+			// a leading byte order mark is ignored in every dialect
+			l.pos += size
+			return
+		}
+		if isWhitespace(r) {
+			if !l.Dialect.Allows(AllowExtraWhitespace) {
+				err = badExtensionError(AllowExtraWhitespace, l.Dialect, l.pos)
+				return
+			}
+			l.pos += size
+			return
+		}
 		l.state = stateValue
+		l.start = l.pos
 	}
 	return
 }
 
-func (l *Lexer) readComment(c byte) (tk Token, err error) {
+func (l *Lexer) readComment(c byte) (typ TokenType, err error) {
 	switch c {
 	case '/':
 		l.state = stateSingleLineComment
@@ -85,79 +165,93 @@ func (l *Lexer) readComment(c byte) (tk Token, err error) {
 		l.state = stateMultipleLineComment
 		l.pos++
 	default:
-		err = badCharError(c, l.pos)
+		err = badCharError(l.str, l.pos)
 	}
 	return
 }
 
-func (l *Lexer) readSingleLineComment(c byte) (tk Token, err error) {
-	switch c {
-	case '\n', '\r':
+func (l *Lexer) readSingleLineComment(c byte) (typ TokenType, err error) {
+	r, size := decodeRune(l, c)
+	if err = l.checkUTF8(r, size); err != nil {
+		return
+	}
+	if isLineTerminator(r) {
 		l.state = stateDefault
-		l.pos++
-	default:
-		l.pos++
 	}
+	l.pos += size
 	return
 }
 
-func (l *Lexer) readMultipleLineComment(c byte) (tk Token, err error) {
+func (l *Lexer) readMultipleLineComment(c byte) (typ TokenType, err error) {
 	switch c {
 	case '*':
 		l.state = stateMultipleLineCommentEndAsterisk
 		l.pos++
 	default:
-		l.pos++
+		r, size := decodeRune(l, c)
+		if err = l.checkUTF8(r, size); err != nil {
+			return
+		}
+		l.pos += size
 	}
 	return
 }
 
-func (l *Lexer) readMultipleLineCommentEndAsterisk(c byte) (tk Token, err error) {
+func (l *Lexer) readMultipleLineCommentEndAsterisk(c byte) (typ TokenType, err error) {
 	switch c {
 	case '/':
 		l.state = stateDefault
 		l.pos++
 	default:
+		// read it again as part of the comment, it may be another '*'
 		l.state = stateMultipleLineComment
-		l.pos++
 	}
 	return
 }
 
-func (l *Lexer) readValue(c byte) (tk Token, err error) {
+func (l *Lexer) readValue(c byte) (typ TokenType, err error) {
 	switch c {
 	// TODO: only this case '[', '{':
 	case '[', ']', '{', '}', ',', ':':
 		l.state = statePunctuator
-	case '"':
+	case '"', '\'':
+		if c == '\'' && !l.Dialect.Allows(AllowSingleQuotes) {
+			err = badExtensionError(AllowSingleQuotes, l.Dialect, l.pos)
+			return
+		}
 		l.state = stateString
+		l.quote = c
 		l.pos++
-	case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+	case '-', '+', '.', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
 		l.state = stateNumber
-	case 'f', 't', 'n':
-		l.state = stateLiteral
+	case '$', '_', '\\':
+		l.state = stateIdentifier
 	default:
-		err = badCharError(c, l.pos)
+		if r, _ := decodeRune(l, c); isIdentifierStart(r) {
+			l.state = stateIdentifier
+		} else {
+			err = badCharError(l.str, l.pos)
+		}
 	}
 	return
 }
 
-func (l *Lexer) readPunctuator(c byte) (tk Token, err error) {
+func (l *Lexer) readPunctuator(c byte) (typ TokenType, err error) {
 	switch c {
 	case '[':
-		tk = Token{TypeArrayBegin, "["}
+		typ = TypeArrayBegin
 	case ']':
-		tk = Token{TypeArrayEnd, "]"}
+		typ = TypeArrayEnd
 	case '{':
-		tk = Token{TypeObjectBegin, "{"}
+		typ = TypeObjectBegin
 	case '}':
-		tk = Token{TypeObjectEnd, "}"}
+		typ = TypeObjectEnd
 	case ',':
-		tk = Token{TypeValueSep, ","}
+		typ = TypeValueSep
 	case ':':
-		tk = Token{TypePairSep, ":"}
+		typ = TypePairSep
 	default:
-		err = badCharError(c, l.pos)
+		err = badCharError(l.str, l.pos)
 		return
 	}
 	l.pos++
@@ -168,26 +262,54 @@ func (l *Lexer) readPunctuator(c byte) (tk Token, err error) {
 // processing string {
 // ================================================================
 
-func (l *Lexer) readString(c byte) (tk Token, err error) {
+func (l *Lexer) readString(c byte) (typ TokenType, err error) {
 	switch c {
 	case '\\':
 		l.state = stateEscapeChar
 		l.pos++
-	case '"':
-		value := l.buf.String()
-		tk = Token{TypeString, value}
+	case l.quote:
+		typ = TypeString
 		l.pos++
 	default:
-		l.buf.Append(c)
-		l.pos++
+		if c < ' ' && !l.AllowControlChars {
+			err = badControlCharError(c, l.pos)
+			return
+		}
+		if end := l.plainRun(); end > l.pos {
+			l.buf.AppendBytes(l.str[l.pos:end])
+			l.pos = end
+			return
+		}
+		err = l.appendChar(c)
 	}
 	return
 }
 
-func (l *Lexer) readEscapeChar(c byte) (tk Token, err error) {
+// plainRun finds the end of the printable ASCII characters in a string that
+// start at the current position, so that they are copied at once. The run
+// stops one byte past MaxStringLength for the limit to be caught.
+func (l *Lexer) plainRun() int {
+	end, max := l.pos, len(l.str)
+	if l.MaxStringLength > 0 && l.start+l.MaxStringLength+2 < max {
+		max = l.start + l.MaxStringLength + 2
+	}
+	for end < max {
+		c := l.str[end]
+		if c < ' ' || c >= utf8.RuneSelf || c == l.quote || c == '\\' {
+			break
+		}
+		end++
+	}
+	return end
+}
+
+func (l *Lexer) readEscapeChar(c byte) (typ TokenType, err error) {
+	if err = l.checkEscapeChar(c); err != nil {
+		return
+	}
 	var value byte
 	switch c {
-	case '"', '\\', '/':
+	case '"', '\'', '\\', '/':
 		value = c
 	case 'b':
 		value = '\b'
@@ -199,10 +321,49 @@ func (l *Lexer) readEscapeChar(c byte) (tk Token, err error) {
 		value = '\r'
 	case 't':
 		value = '\t'
+	case 'v':
+		value = '\v'
+	case '0':
+		if l.pos+1 < len(l.str) && isDigit(l.str[l.pos+1]) {
+			err = badEscapeError(string(l.str[l.pos-1:l.pos+2]), l.pos-1)
+			return
+		}
+		value = 0
+	case '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		err = badEscapeError(string(l.str[l.pos-1:l.pos+1]), l.pos-1)
+		return
+	case 'x':
+		p0 := l.pos - 1
+		l.pos++
+		r, ok := expectHexDigits(l, 2)
+		if !ok {
+			err = badHexDigitError(l.str, l.pos, p0)
+			return
+		}
+		l.state = stateString
+		l.buf.AppendRune(r)
+		return
 	case 'u':
-		value = 0 // TODO: support unicode
+		l.pos++
+		err = l.readUnicodeEscape(l.pos - 2)
+		return
 	default:
-		err = badCharError(c, l.pos)
+		l.state = stateString
+		r, size := decodeRune(l, c)
+		if !isLineTerminator(r) {
+			if c < ' ' && !l.AllowControlChars {
+				err = badControlCharError(c, l.pos)
+				return
+			}
+			// any other character stands for itself
+			err = l.appendChar(c)
+			return
+		}
+		// a line continuation contributes nothing to the value
+		l.pos += size
+		if r == '\r' && l.pos < len(l.str) && l.str[l.pos] == '\n' {
+			l.pos++
+		}
 		return
 	}
 	l.state = stateString
@@ -211,6 +372,82 @@ func (l *Lexer) readEscapeChar(c byte) (tk Token, err error) {
 	return
 }
 
+// checkEscapeChar rejects the escape sequences that strict JSON does not
+// know unless the dialect allows them
+func (l *Lexer) checkEscapeChar(c byte) error {
+	switch c {
+	case '"', '\\', '/', 'b', 'f', 'n', 'r', 't', 'u':
+		return nil
+	}
+	ext := AllowExtraEscapes
+	if r, _ := decodeRune(l, c); isLineTerminator(r) {
+		ext = AllowMultilineStrings
+	}
+	if !l.Dialect.Allows(ext) {
+		return badExtensionError(ext, l.Dialect, l.pos-1)
+	}
+	return nil
+}
+
+// appendChar copies the character starting at the current position into the
+// buffer, handling invalid UTF-8 according to the InvalidUTF8 policy
+func (l *Lexer) appendChar(c byte) error {
+	if c < utf8.RuneSelf {
+		l.buf.Append(c)
+		l.pos++
+		return nil
+	}
+	r, size := decodeRune(l, c)
+	if err := l.checkUTF8(r, size); err != nil {
+		return err
+	}
+	if r == utf8.RuneError && size == 1 && l.InvalidUTF8 == UTF8Pass {
+		l.buf.Append(c)
+	} else {
+		l.buf.AppendRune(r)
+	}
+	l.pos += size
+	return nil
+}
+
+// checkUTF8 rejects an invalid byte at the current position when the
+// InvalidUTF8 policy asks for it
+func (l *Lexer) checkUTF8(r rune, size int) error {
+	if r == utf8.RuneError && size == 1 && l.InvalidUTF8 == UTF8Reject {
+		return badUTF8Error(l.str[l.pos], l.pos)
+	}
+	return nil
+}
+
+// readUnicodeEscape decodes the four hex digits of a \uXXXX sequence. A high
+// surrogate immediately followed by an escaped low surrogate is combined into
+// a single code point; lone or unpaired surrogates are replaced with U+FFFD.
+func (l *Lexer) readUnicodeEscape(start int) error {
+	r, ok := expectHexDigits(l, 4)
+	if !ok {
+		return badHexDigitError(l.str, l.pos, start)
+	}
+	if utf16.IsSurrogate(r) {
+		high := r
+		r = utf8.RuneError
+		if high < 0xDC00 && bytes.HasPrefix(l.str[l.pos:], []byte(`\u`)) {
+			p0 := l.pos
+			l.pos += 2
+			low, ok := expectHexDigits(l, 4)
+			if !ok {
+				return badHexDigitError(l.str, l.pos, p0)
+			}
+			if r = utf16.DecodeRune(high, low); r == utf8.RuneError {
+				// not a low surrogate, leave it for the next round
+				l.pos = p0
+			}
+		}
+	}
+	l.state = stateString
+	l.buf.AppendRune(r)
+	return nil
+}
+
 // ================================================================
 // }
 // ================================================================
@@ -219,19 +456,23 @@ func (l *Lexer) readEscapeChar(c byte) (tk Token, err error) {
 // processing number {
 // ================================================================
 
-func (l *Lexer) readNumber(c byte) (tk Token, err error) {
+func (l *Lexer) readNumber(c byte) (typ TokenType, err error) {
 	switch c {
-	case '-':
+	case '-', '+':
+		if c == '+' && !l.Dialect.Allows(AllowLeadingPlus) {
+			err = badExtensionError(AllowLeadingPlus, l.Dialect, l.pos)
+			return
+		}
 		l.state = stateUnsignedNumber
 		l.buf.Append(c)
 		l.pos++
 	default:
-		tk, err = l.readUnsignedNumber(c)
+		typ, err = l.readUnsignedNumber(c)
 	}
 	return
 }
 
-func (l *Lexer) readUnsignedNumber(c byte) (tk Token, err error) {
+func (l *Lexer) readUnsignedNumber(c byte) (typ TokenType, err error) {
 	switch c {
 	case '0':
 		l.state = stateZero
@@ -241,13 +482,42 @@ func (l *Lexer) readUnsignedNumber(c byte) (tk Token, err error) {
 		l.state = stateDecimalInteger
 		l.buf.Append(c)
 		l.pos++
+	case '.':
+		if !l.Dialect.Allows(AllowRelaxedDecimals) {
+			err = badExtensionError(AllowRelaxedDecimals, l.Dialect, l.pos)
+			return
+		}
+		l.state = stateLeadingPoint
+		l.buf.Append(c)
+		l.pos++
+	case 'I':
+		typ, err = l.readSignedLiteral(TypeInfinity, "Infinity")
+	case 'N':
+		typ, err = l.readSignedLiteral(TypeNaN, "NaN")
 	default:
-		err = badCharError(c, l.pos)
+		err = badCharError(l.str, l.pos)
 	}
 	return
 }
 
-func (l *Lexer) readZero(c byte) (tk Token, err error) {
+// readSignedLiteral reads Infinity or NaN following an explicit sign; the
+// unsigned forms are recognized as identifiers
+func (l *Lexer) readSignedLiteral(literalType TokenType, literal string) (typ TokenType, err error) {
+	p0 := l.pos
+	if !expectLiteral(l, literal) {
+		if l.pos >= len(l.str) && bytes.HasPrefix([]byte(literal), l.str[p0:]) {
+			err = unterminatedError("number", l.start)
+			return
+		}
+		err = badTokenError(string(l.str[l.start:l.pos]), l.start)
+		return
+	}
+	l.buf.AppendString(literal)
+	typ = literalType
+	return
+}
+
+func (l *Lexer) readZero(c byte) (typ TokenType, err error) {
 	switch c {
 	case '.':
 		l.state = statePoint
@@ -257,14 +527,21 @@ func (l *Lexer) readZero(c byte) (tk Token, err error) {
 		l.state = stateDecimalExponent
 		l.buf.Append(c)
 		l.pos++
-	// case 'x', 'X': TODO: support hexadecimal number
+	case 'x', 'X':
+		if !l.Dialect.Allows(AllowHexNumbers) {
+			err = badExtensionError(AllowHexNumbers, l.Dialect, l.start)
+			return
+		}
+		l.state = stateHexPrefix
+		l.buf.Append(c)
+		l.pos++
 	default:
-		tk = Token{TypeInteger, l.buf.String()}
+		typ = TypeInteger
 	}
 	return
 }
 
-func (l *Lexer) readDecimalInteger(c byte) (tk Token, err error) {
+func (l *Lexer) readDecimalInteger(c byte) (typ TokenType, err error) {
 	switch c {
 	case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
 		l.buf.Append(c)
@@ -278,24 +555,72 @@ func (l *Lexer) readDecimalInteger(c byte) (tk Token, err error) {
 		l.buf.Append(c)
 		l.pos++
 	default:
-		tk = Token{TypeInteger, l.buf.String()}
+		typ = TypeInteger
 	}
 	return
 }
 
-func (l *Lexer) readPoint(c byte) (tk Token, err error) {
+func (l *Lexer) readHexPrefix(c byte) (typ TokenType, err error) {
+	if !isHexDigit(c) {
+		err = badCharError(l.str, l.pos)
+		return
+	}
+	l.state = stateHexInteger
+	l.buf.Append(c)
+	l.pos++
+	return
+}
+
+func (l *Lexer) readHexInteger(c byte) (typ TokenType, err error) {
+	if !isHexDigit(c) {
+		typ = TypeHexInteger
+		return
+	}
+	l.buf.Append(c)
+	l.pos++
+	return
+}
+
+// readLeadingPoint handles a number starting with a decimal point, e.g. .5,
+// which must be followed by at least one digit
+func (l *Lexer) readLeadingPoint(c byte) (typ TokenType, err error) {
 	switch c {
 	case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
 		l.state = stateDecimalFraction
 		l.buf.Append(c)
 		l.pos++
 	default:
-		err = badCharError(c, l.pos)
+		err = badCharError(l.str, l.pos)
+	}
+	return
+}
+
+// readPoint handles the decimal point after an integer part; the fraction
+// digits are optional, e.g. 5. and 5.e3
+func (l *Lexer) readPoint(c byte) (typ TokenType, err error) {
+	switch c {
+	case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		l.state = stateDecimalFraction
+		l.buf.Append(c)
+		l.pos++
+		return
+	}
+	if !l.Dialect.Allows(AllowRelaxedDecimals) {
+		err = badExtensionError(AllowRelaxedDecimals, l.Dialect, l.pos-1)
+		return
+	}
+	switch c {
+	case 'e', 'E':
+		l.state = stateDecimalExponent
+		l.buf.Append(c)
+		l.pos++
+	default:
+		typ = TypeFloat
 	}
 	return
 }
 
-func (l *Lexer) readDecimalFraction(c byte) (tk Token, err error) {
+func (l *Lexer) readDecimalFraction(c byte) (typ TokenType, err error) {
 	switch c {
 	case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
 		l.buf.Append(c)
@@ -305,30 +630,42 @@ func (l *Lexer) readDecimalFraction(c byte) (tk Token, err error) {
 		l.buf.Append(c)
 		l.pos++
 	default:
-		tk = Token{TypeFloat, l.buf.String()}
+		typ = TypeFloat
 	}
 	return
 }
 
-func (l *Lexer) readDecimalExponent(c byte) (tk Token, err error) {
+func (l *Lexer) readDecimalExponent(c byte) (typ TokenType, err error) {
 	switch c {
 	case '+', '-':
 		l.state = stateUnsignedDecimalExponent
 		l.buf.Append(c)
 		l.pos++
 	default:
-		tk, err = l.readUnsignedDecimalExponent(c)
+		typ, err = l.readUnsignedDecimalExponent(c)
 	}
 	return
 }
 
-func (l *Lexer) readUnsignedDecimalExponent(c byte) (tk Token, err error) {
+func (l *Lexer) readUnsignedDecimalExponent(c byte) (typ TokenType, err error) {
 	switch c {
 	case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		l.state = stateDecimalExponentInteger
 		l.buf.Append(c)
 		l.pos++
 	default:
-		tk = Token{TypeFloat, l.buf.String()}
+		err = badCharError(l.str, l.pos)
+	}
+	return
+}
+
+func (l *Lexer) readDecimalExponentInteger(c byte) (typ TokenType, err error) {
+	switch c {
+	case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		l.buf.Append(c)
+		l.pos++
+	default:
+		typ = TypeFloat
 	}
 	return
 }
@@ -337,39 +674,173 @@ func (l *Lexer) readUnsignedDecimalExponent(c byte) (tk Token, err error) {
 // }
 // ================================================================
 
-func (l *Lexer) readLiteral(c byte) (tk Token, err error) {
-	p0 := l.pos
-	switch c {
-	case 'f':
-		if expectLiteral(l, "false") {
-			tk = Token{TypeFalse, "false"}
-			return
-		}
-	case 't':
-		if expectLiteral(l, "true") {
-			tk = Token{TypeTrue, "true"}
-			return
-		}
-	case 'n':
-		if expectLiteral(l, "null") {
-			tk = Token{TypeNull, "null"}
-			return
-		}
+// ================================================================
+// processing identifier {
+// ================================================================
+
+func (l *Lexer) readIdentifier(c byte) (typ TokenType, err error) {
+	if c == '\\' {
+		l.state = stateIdentifierEscape
+		l.pos++
+		return
+	}
+	r, size := decodeRune(l, c)
+	if !isIdentifierStart(r) {
+		err = badCharError(l.str, l.pos)
+		return
 	}
-	err = badTokenError(string(l.str[p0:l.pos]), p0)
+	l.state = stateIdentifierPart
+	l.buf.AppendRune(r)
+	l.pos += size
 	return
 }
 
+func (l *Lexer) readIdentifierPart(c byte) (typ TokenType, err error) {
+	if c == '\\' {
+		l.state = stateIdentifierEscape
+		l.pos++
+		return
+	}
+	r, size := decodeRune(l, c)
+	if !isIdentifierPart(r) {
+		typ = l.identifierType()
+		return
+	}
+	l.buf.AppendRune(r)
+	l.pos += size
+	return
+}
+
+func (l *Lexer) readIdentifierEscape(c byte) (typ TokenType, err error) {
+	if c != 'u' {
+		err = badCharError(l.str, l.pos)
+		return
+	}
+	p0 := l.pos - 1
+	l.pos++
+	r, ok := expectHexDigits(l, 4)
+	if !ok {
+		err = badHexDigitError(l.str, l.pos, p0)
+		return
+	}
+	// the first character of a name must be a start character even if it
+	// is written as an escape sequence
+	if (p0 == l.start && !isIdentifierStart(r)) || !isIdentifierPart(r) {
+		err = badTokenError(string(l.str[p0:l.pos]), p0)
+		return
+	}
+	l.state = stateIdentifierPart
+	l.buf.AppendRune(r)
+	return
+}
+
+// identifierType classifies the identifier that was just read. Reserved
+// words are only recognized when spelled without escape sequences.
+func (l *Lexer) identifierType() TokenType {
+	if src := l.str[l.start:l.pos]; bytes.IndexByte(src, '\\') < 0 {
+		switch string(src) {
+		case "false":
+			return TypeFalse
+		case "true":
+			return TypeTrue
+		case "null":
+			return TypeNull
+		case "Infinity":
+			return TypeInfinity
+		case "NaN":
+			return TypeNaN
+		}
+	}
+	return TypeIdentifier
+}
+
+// token builds the token of the given type that was just read
+func (l *Lexer) token(typ TokenType) Token {
+	tk := Token{Type: typ}
+	switch typ {
+	case TypeArrayBegin:
+		tk.Raw = "["
+	case TypeArrayEnd:
+		tk.Raw = "]"
+	case TypeObjectBegin:
+		tk.Raw = "{"
+	case TypeObjectEnd:
+		tk.Raw = "}"
+	case TypeValueSep:
+		tk.Raw = ","
+	case TypePairSep:
+		tk.Raw = ":"
+	case TypeFalse:
+		tk.Raw = "false"
+	case TypeTrue:
+		tk.Raw = "true"
+	case TypeNull:
+		tk.Raw = "null"
+	case TypeString, TypeInteger, TypeHexInteger, TypeFloat, TypeInfinity, TypeNaN, TypeIdentifier:
+		tk.Raw = l.buf.String()
+	}
+	return tk
+}
+
+// ================================================================
+// }
+// ================================================================
+
 // TODO: new state handler
 // func (l *Lexer) readXxx() {}
 
-func (l *Lexer) checkEndState() error {
+// readEOF finishes the current state at the end of input. Tokens that may
+// end anywhere are completed, while unterminated constructs are reported at
+// the position where they started.
+func (l *Lexer) readEOF() (typ TokenType, err error) {
 	switch l.state {
-	case stateMultipleLineComment, stateMultipleLineCommentEndAsterisk:
-		return badEOF(l.pos)
+	case stateDefault, stateSingleLineComment:
+		l.start = l.pos
+		typ = TypeEOF
+	case stateComment, stateMultipleLineComment, stateMultipleLineCommentEndAsterisk:
+		err = unterminatedError("comment", l.start)
+	case stateString:
+		err = unterminatedError("string", l.start)
+	case stateEscapeChar, stateIdentifierEscape:
+		err = unterminatedError("escape sequence", l.pos-1)
+	case stateUnsignedNumber, stateHexPrefix, stateLeadingPoint,
+		stateDecimalExponent, stateUnsignedDecimalExponent:
+		err = unterminatedError("number", l.start)
+	case stateZero, stateDecimalInteger:
+		typ = TypeInteger
+	case stateHexInteger:
+		typ = TypeHexInteger
+	case statePoint:
+		if !l.Dialect.Allows(AllowRelaxedDecimals) {
+			err = badExtensionError(AllowRelaxedDecimals, l.Dialect, l.pos-1)
+			return
+		}
+		typ = TypeFloat
+	case stateDecimalFraction, stateDecimalExponentInteger:
+		typ = TypeFloat
+	case stateIdentifierPart:
+		typ = l.identifierType()
 	default:
-		return nil
+		// the remaining states are only entered with a character at hand
+		panic("unreachable")
 	}
+	return
+}
+
+// locate fills in the source position of a token that has just been read,
+// or of the error that prevented it
+func (l *Lexer) locate(tk *Token, err error) {
+	if e, ok := err.(*SyntaxError); ok {
+		e.locate(l.str)
+	}
+	if tk.Type == TypeNone {
+		return
+	}
+	l.lines.Advance(l.str, l.start)
+	tk.Start = l.start
+	tk.End = l.pos
+	tk.Line = l.lines.line + 1
+	tk.Column = l.lines.column + 1
 }
 
 // Reset resets the internals for next token
@@ -378,67 +849,113 @@ func (l *Lexer) Reset() {
 	l.buf.Reset()
 }
 
+// checkLength fails as soon as the string, identifier or number being read
+// grows beyond its limit
+func (l *Lexer) checkLength() error {
+	n := l.pos - l.start
+	switch l.state {
+	case stateString, stateEscapeChar:
+		// without the opening quote
+		if l.MaxStringLength > 0 && n-1 > l.MaxStringLength {
+			return limitError(KindStringTooLong, "string", l.MaxStringLength, l.start)
+		}
+	case stateIdentifierPart, stateIdentifierEscape:
+		if l.MaxStringLength > 0 && n > l.MaxStringLength {
+			return limitError(KindStringTooLong, "identifier", l.MaxStringLength, l.start)
+		}
+	case stateNumber, stateUnsignedNumber, stateZero, stateDecimalInteger,
+		stateHexPrefix, stateHexInteger, stateLeadingPoint, statePoint,
+		stateDecimalFraction, stateDecimalExponent, stateUnsignedDecimalExponent,
+		stateDecimalExponentInteger:
+		if l.MaxNumberLength > 0 && n > l.MaxNumberLength {
+			return limitError(KindNumberTooLong, "number literal", l.MaxNumberLength, l.start)
+		}
+	}
+	return nil
+}
+
+// reset prepares the lexer to scan s from the beginning
+func (l *Lexer) reset(s []byte) {
+	l.str = s
+	l.pos = 0
+	l.start = 0
+	l.quote = 0
+	l.lines = lineCounter{}
+	l.Reset()
+}
+
 // Token gets the next JSON token
 func (l *Lexer) Token() (tk Token, err error) {
 	l.Reset()
+	var typ TokenType
+	limited := l.MaxStringLength > 0 || l.MaxNumberLength > 0
 	for {
-		var c byte
-		if l.pos < len(l.str) {
-			c = l.str[l.pos]
-		} else {
-			c = ' '
+		if l.pos >= len(l.str) {
+			typ, err = l.readEOF()
+			break
 		}
+		c := l.str[l.pos]
 		switch l.state {
 		case stateDefault:
-			tk, err = l.readDefault(c)
+			typ, err = l.readDefault(c)
 		case stateComment:
-			tk, err = l.readComment(c)
+			typ, err = l.readComment(c)
 		case stateSingleLineComment:
-			tk, err = l.readSingleLineComment(c)
+			typ, err = l.readSingleLineComment(c)
 		case stateMultipleLineComment:
-			tk, err = l.readMultipleLineComment(c)
+			typ, err = l.readMultipleLineComment(c)
 		case stateMultipleLineCommentEndAsterisk:
-			tk, err = l.readMultipleLineCommentEndAsterisk(c)
+			typ, err = l.readMultipleLineCommentEndAsterisk(c)
 		case stateValue:
-			tk, err = l.readValue(c)
+			typ, err = l.readValue(c)
 		case statePunctuator:
-			tk, err = l.readPunctuator(c)
+			typ, err = l.readPunctuator(c)
 		case stateString:
-			tk, err = l.readString(c)
+			typ, err = l.readString(c)
 		case stateEscapeChar:
-			tk, err = l.readEscapeChar(c)
+			typ, err = l.readEscapeChar(c)
 		case stateNumber:
-			tk, err = l.readNumber(c)
+			typ, err = l.readNumber(c)
 		case stateUnsignedNumber:
-			tk, err = l.readUnsignedNumber(c)
+			typ, err = l.readUnsignedNumber(c)
 		case stateZero:
-			tk, err = l.readZero(c)
+			typ, err = l.readZero(c)
 		case stateDecimalInteger:
-			tk, err = l.readDecimalInteger(c)
+			typ, err = l.readDecimalInteger(c)
+		case stateHexPrefix:
+			typ, err = l.readHexPrefix(c)
+		case stateHexInteger:
+			typ, err = l.readHexInteger(c)
+		case stateLeadingPoint:
+			typ, err = l.readLeadingPoint(c)
 		case statePoint:
-			tk, err = l.readPoint(c)
+			typ, err = l.readPoint(c)
 		case stateDecimalFraction:
-			tk, err = l.readDecimalFraction(c)
+			typ, err = l.readDecimalFraction(c)
 		case stateDecimalExponent:
-			tk, err = l.readDecimalExponent(c)
+			typ, err = l.readDecimalExponent(c)
 		case stateUnsignedDecimalExponent:
-			tk, err = l.readUnsignedDecimalExponent(c)
-		case stateLiteral:
-			tk, err = l.readLiteral(c)
+			typ, err = l.readUnsignedDecimalExponent(c)
+		case stateDecimalExponentInteger:
+			typ, err = l.readDecimalExponentInteger(c)
+		case stateIdentifier:
+			typ, err = l.readIdentifier(c)
+		case stateIdentifierPart:
+			typ, err = l.readIdentifierPart(c)
+		case stateIdentifierEscape:
+			typ, err = l.readIdentifierEscape(c)
 		}
-		// check EOF
-		if l.pos > len(l.str) {
-			// check state
-			if e := l.checkEndState(); e != nil {
-				err = e
-			} else {
-				// exit normally
-				tk = Token{TypeEOF, ""}
-			}
+		if limited && typ == TypeNone && err == nil {
+			err = l.checkLength()
 		}
 		// check result and error
-		if tk.Type != TypeNone || err != nil {
-			return
+		if typ != TypeNone || err != nil {
+			break
 		}
 	}
+	if typ != TypeNone {
+		tk = l.token(typ)
+	}
+	l.locate(&tk, err)
+	return
 }
@@ -25,6 +25,25 @@ func TestReadString(t *testing.T) {
 	expectToken(t, t1, json5.TypeEOF)
 }
 
+func TestReadSingleQuotedString(t *testing.T) {
+	samples := []string{
+		` 'foo' `, `'say "hi"'`, `'it\'s'`, `''`,
+	}
+	expectedValues := []string{
+		"foo", "say \"hi\"", "it's", "",
+	}
+	for idx, sample := range samples {
+		lexer := json5.Scan(sample)
+		t0, err := lexer.Token()
+		noError(t, err)
+		expectToken(t, t0, json5.TypeString)
+		equals(t, expectedValues[idx], t0.Raw)
+		t1, err := lexer.Token()
+		noError(t, err)
+		expectToken(t, t1, json5.TypeEOF)
+	}
+}
+
 func TestReadEscapeChar(t *testing.T) {
 	lexer := json5.Scan(` "foo\"bar" `)
 	t0, err := lexer.Token()
@@ -38,10 +57,33 @@ func TestReadEscapeChar(t *testing.T) {
 
 func TestReadValidEscapeChars(t *testing.T) {
 	samples := []string{
-		`"\""`, `"\\"`, `"\/"`, `"\b"`, `"\f"`, `"\n"`, `"\r"`, `"\t"`,
+		`"\""`, `"\'"`, `"\\"`, `"\/"`, `"\b"`, `"\f"`, `"\n"`, `"\r"`, `"\t"`, `"\v"`,
+		`"\0"`, `"\0a"`, `"\x41"`, `"\xe9"`, `"\a"`, `"\e"`, `"\?"`, `"\ü"`,
+	}
+	expectedValues := []string{
+		"\"", "'", "\\", "/", "\b", "\f", "\n", "\r", "\t", "\v",
+		"\x00", "\x00a", "A", "é", "a", "e", "?", "ü",
+	}
+	for idx, sample := range samples {
+		lexer := json5.Scan(sample)
+		t0, err := lexer.Token()
+		noError(t, err)
+		expectToken(t, t0, json5.TypeString)
+		equals(t, expectedValues[idx], t0.Raw)
+		t1, err := lexer.Token()
+		noError(t, err)
+		expectToken(t, t1, json5.TypeEOF)
+	}
+}
+
+func TestReadLineContinuation(t *testing.T) {
+	samples := []string{
+		"'foo\\\nbar'", "'foo\\\rbar'", "'foo\\\r\nbar'", "'foo\\\u2028bar'", "'foo\\\u2029bar'",
+		"'foo\\\n\\\nbar'", "'foo \\\r\n  bar'", "'\\\r\n'",
 	}
 	expectedValues := []string{
-		"\"", "\\", "/", "\b", "\f", "\n", "\r", "\t",
+		"foobar", "foobar", "foobar", "foobar", "foobar",
+		"foobar", "foo   bar", "",
 	}
 	for idx, sample := range samples {
 		lexer := json5.Scan(sample)
@@ -55,18 +97,230 @@ func TestReadValidEscapeChars(t *testing.T) {
 	}
 }
 
+func TestReadUnicodeEscape(t *testing.T) {
+	samples := []string{
+		`"\u0041"`, `"\u00e9"`, `"\u00E9t\u00e9"`, `"\u4e2d\u6587"`, `"\ud83d\ude00"`, `"\uD834\uDD1E!"`,
+	}
+	expectedValues := []string{
+		"A", "é", "été", "中文", "😀", "𝄞!",
+	}
+	for idx, sample := range samples {
+		lexer := json5.Scan(sample)
+		t0, err := lexer.Token()
+		noError(t, err)
+		expectToken(t, t0, json5.TypeString)
+		equals(t, expectedValues[idx], t0.Raw)
+	}
+}
+
+func TestReadLoneSurrogateEscape(t *testing.T) {
+	samples := []string{
+		`"\ud83d"`, `"\ude00"`, `"\ud83dx"`, `"\ud83d\u0041"`, `"\ude00\ud83d"`, `"\ud83d\ud83d\ude00"`,
+	}
+	expectedValues := []string{
+		"\uFFFD", "\uFFFD", "\uFFFDx", "\uFFFDA", "\uFFFD\uFFFD", "\uFFFD😀",
+	}
+	for idx, sample := range samples {
+		lexer := json5.Scan(sample)
+		t0, err := lexer.Token()
+		noError(t, err)
+		expectToken(t, t0, json5.TypeString)
+		equals(t, expectedValues[idx], t0.Raw)
+	}
+}
+
+func TestReadInvalidUnicodeEscape(t *testing.T) {
+	samples := []string{
+		`"\u12"`, `"\u12G4"`, `"\uD83D\uDE0"`, `"\u"`,
+	}
+	expectedErrors := []string{
+		`invalid hexadecimal digit: " at position 5`,
+		`invalid hexadecimal digit: G at position 5`,
+		`invalid hexadecimal digit: " at position 12`,
+		`invalid hexadecimal digit: " at position 3`,
+	}
+	for idx, sample := range samples {
+		lexer := json5.Scan(sample)
+		t0, err := lexer.Token()
+		hasError(t, err, expectedErrors[idx])
+		expectToken(t, t0, json5.TypeNone)
+	}
+	lexer := json5.Scan(`"\u00`)
+	_, err := lexer.Token()
+	hasError(t, err, "unexpected end of JSON: unterminated escape sequence at position 1")
+}
+
 func TestReadInvalidEscapeChars(t *testing.T) {
 	samples := []string{
-		`"\a"`, `"\e"`, `"\v"`, `"\'"`, `"\?"`, `"\x"`,
+		`"\1"`, `"\9"`, `"\01"`, `"\x"`, `"\x4"`, `"\xG0"`,
 	}
-	for _, sample := range samples {
+	expectedErrors := []string{
+		`invalid escape sequence: \1 at position 1`,
+		`invalid escape sequence: \9 at position 1`,
+		`invalid escape sequence: \01 at position 1`,
+		`invalid hexadecimal digit: " at position 3`,
+		`invalid hexadecimal digit: " at position 4`,
+		`invalid hexadecimal digit: G at position 3`,
+	}
+	for idx, sample := range samples {
 		lexer := json5.Scan(sample)
 		t0, err := lexer.Token()
-		hasError(t, err, "unexpected character")
+		hasError(t, err, expectedErrors[idx])
 		expectToken(t, t0, json5.TypeNone)
 	}
 }
 
+func TestReadControlCharsInString(t *testing.T) {
+	samples := []string{
+		"'a\nb'", "'a\rb'", "'a\x00b'", "'a\tb'", "'a\x1fb'",
+		"\"a\\\x00b\"", "'a\\\x1fb'", "'a\\\tb'",
+	}
+	expectedErrors := []string{
+		"invalid control character in string: U+000A at position 2",
+		"invalid control character in string: U+000D at position 2",
+		"invalid control character in string: U+0000 at position 2",
+		"invalid control character in string: U+0009 at position 2",
+		"invalid control character in string: U+001F at position 2",
+		"invalid control character in string: U+0000 at position 3",
+		"invalid control character in string: U+001F at position 3",
+		"invalid control character in string: U+0009 at position 3",
+	}
+	for idx, sample := range samples {
+		lexer := json5.Scan(sample)
+		t0, err := lexer.Token()
+		hasError(t, err, expectedErrors[idx])
+		expectToken(t, t0, json5.TypeNone)
+
+		lexer = json5.Scan(sample)
+		lexer.AllowControlChars = true
+		t0, err = lexer.Token()
+		noError(t, err)
+		expectToken(t, t0, json5.TypeString)
+		// an escaped control character stands for itself
+		equals(t, strings.Replace(sample[1:len(sample)-1], "\\", "", 1), t0.Raw)
+	}
+}
+
+func TestReadInvalidUTF8String(t *testing.T) {
+	sample := "'a\xffb\\\xfe'"
+	lexer := json5.Scan(sample)
+	_, err := lexer.Token()
+	hasError(t, err, "invalid UTF-8 byte: 0xff at position 2")
+
+	policies := []json5.UTF8Policy{json5.UTF8Replace, json5.UTF8Pass}
+	expectedValues := []string{"a\uFFFDb\uFFFD", "a\xffb\xfe"}
+	for idx, policy := range policies {
+		lexer := json5.Scan(sample)
+		lexer.InvalidUTF8 = policy
+		t0, err := lexer.Token()
+		noError(t, err)
+		expectToken(t, t0, json5.TypeString)
+		equals(t, expectedValues[idx], t0.Raw)
+	}
+}
+
+func TestReadValidUTF8String(t *testing.T) {
+	lexer := json5.Scan("'h\u00e9llo \u4e16\u754c \U0001F600 \uFFFD'")
+	t0, err := lexer.Token()
+	noError(t, err)
+	expectToken(t, t0, json5.TypeString)
+	equals(t, "h\u00e9llo \u4e16\u754c \U0001F600 \uFFFD", t0.Raw)
+}
+
+func TestReadInvalidUTF8Comment(t *testing.T) {
+	samples := []string{
+		"// \xc3\x28\nnull", "/* \xe2\x82 */ null",
+	}
+	for _, sample := range samples {
+		lexer := json5.Scan(sample)
+		_, err := lexer.Token()
+		hasError(t, err, "invalid UTF-8 byte")
+
+		lexer = json5.Scan(sample)
+		lexer.InvalidUTF8 = json5.UTF8Replace
+		t0, err := lexer.Token()
+		noError(t, err)
+		expectToken(t, t0, json5.TypeNull)
+	}
+}
+
+func TestReadInvalidUTF8Identifier(t *testing.T) {
+	samples := []string{
+		"\xffabc", "ab\xffc",
+	}
+	for _, sample := range samples {
+		lexer := json5.Scan(sample)
+		lexer.InvalidUTF8 = json5.UTF8Pass
+		var err error
+		for err == nil {
+			var tk json5.Token
+			if tk, err = lexer.Token(); tk.Type == json5.TypeEOF {
+				break
+			}
+		}
+		hasError(t, err, "invalid UTF-8 byte: 0xff")
+	}
+}
+
+func TestReadInvalidMultiByteChar(t *testing.T) {
+	samples := []string{
+		`€`, `1€`, `abc×`, `-ü`,
+	}
+	expectedErrors := []string{
+		"unexpected character: € at position 0",
+		"unexpected character: € at position 1",
+		"unexpected character: × at position 3",
+		"unexpected character: ü at position 1",
+	}
+	for idx, sample := range samples {
+		lexer := json5.Scan(sample)
+		var err error
+		for err == nil {
+			var tk json5.Token
+			if tk, err = lexer.Token(); tk.Type == json5.TypeEOF {
+				break
+			}
+		}
+		hasError(t, err, expectedErrors[idx])
+	}
+}
+
+func TestReadInvalidByteOutsideString(t *testing.T) {
+	samples := []string{
+		"0x\xff", "1e\xff", "/\xff", "1\xff", ".\xff", "1\x01", "\x7f",
+	}
+	expectedErrors := []string{
+		"invalid UTF-8 byte: 0xff at position 2",
+		"invalid UTF-8 byte: 0xff at position 2",
+		"invalid UTF-8 byte: 0xff at position 1",
+		"invalid UTF-8 byte: 0xff at position 1",
+		"invalid UTF-8 byte: 0xff at position 1",
+		"unexpected character: U+0001 at position 1",
+		"unexpected character: U+007F at position 0",
+	}
+	expectedKinds := []json5.ErrorKind{
+		json5.KindInvalidUTF8,
+		json5.KindInvalidUTF8,
+		json5.KindInvalidUTF8,
+		json5.KindInvalidUTF8,
+		json5.KindInvalidUTF8,
+		json5.KindUnexpectedCharacter,
+		json5.KindUnexpectedCharacter,
+	}
+	for idx, sample := range samples {
+		lexer := json5.Scan(sample)
+		var err error
+		for err == nil {
+			var tk json5.Token
+			if tk, err = lexer.Token(); tk.Type == json5.TypeEOF {
+				break
+			}
+		}
+		hasError(t, err, expectedErrors[idx])
+		equals(t, expectedKinds[idx], err.(*json5.SyntaxError).Kind)
+	}
+}
+
 func TestReadIntegerNumber(t *testing.T) {
 	lexer := json5.Scan(` 5 `)
 	t0, err := lexer.Token()
@@ -136,13 +390,100 @@ func TestReadValidNumbers(t *testing.T) {
 	}
 }
 
-func TestReadInvalidNumber(t *testing.T) {
-	lexer := json5.Scan(` 3.e8 `)
+func TestReadHexNumbers(t *testing.T) {
+	samples := []string{
+		" 0x1F ", " 0XAB ", " -0xff ", " +0xA ", " 0x0 ",
+	}
+	for _, sample := range samples {
+		expectedValue := strings.Trim(sample, " ")
+		lexer := json5.Scan(sample)
+		t0, err := lexer.Token()
+		noError(t, err)
+		expectToken(t, t0, json5.TypeHexInteger)
+		equals(t, expectedValue, t0.Raw)
+		t1, err := lexer.Token()
+		noError(t, err)
+		expectToken(t, t1, json5.TypeEOF)
+	}
+}
+
+func TestReadInvalidHexNumber(t *testing.T) {
+	lexer := json5.Scan(` 0xG `)
 	t0, err := lexer.Token()
-	hasError(t, err, "unexpected character")
+	hasError(t, err, "unexpected character: G")
 	expectToken(t, t0, json5.TypeNone)
 }
 
+func TestReadRelaxedNumbers(t *testing.T) {
+	samples := [][]string{
+		{" +1 ", " +0 ", " +12 "},
+		{" .5 ", " 5. ", " -.5 ", " +.5 ", " +5. ", " 3.e8 ", " .5e2 ", " 0.e-1 ", " +1.5E+3 "},
+	}
+	expectedTypes := []json5.TokenType{
+		json5.TypeInteger,
+		json5.TypeFloat,
+	}
+	for idx, line := range samples {
+		expectedType := expectedTypes[idx]
+		for _, sample := range line {
+			expectedValue := strings.Trim(sample, " ")
+			lexer := json5.Scan(sample)
+			t0, err := lexer.Token()
+			noError(t, err)
+			expectToken(t, t0, expectedType)
+			equals(t, expectedValue, t0.Raw)
+			t1, err := lexer.Token()
+			noError(t, err)
+			expectToken(t, t1, json5.TypeEOF)
+		}
+	}
+}
+
+func TestReadInfinityAndNaN(t *testing.T) {
+	samples := []string{
+		" Infinity ", " +Infinity ", " -Infinity ", " NaN ", " +NaN ", " -NaN ",
+	}
+	expectedTypes := []json5.TokenType{
+		json5.TypeInfinity, json5.TypeInfinity, json5.TypeInfinity,
+		json5.TypeNaN, json5.TypeNaN, json5.TypeNaN,
+	}
+	for idx, sample := range samples {
+		expectedValue := strings.Trim(sample, " ")
+		lexer := json5.Scan(sample)
+		t0, err := lexer.Token()
+		noError(t, err)
+		expectToken(t, t0, expectedTypes[idx])
+		equals(t, expectedValue, t0.Raw)
+		t1, err := lexer.Token()
+		noError(t, err)
+		expectToken(t, t1, json5.TypeEOF)
+	}
+}
+
+func TestReadInvalidSignedLiteral(t *testing.T) {
+	samples := []string{
+		` -Infinit `, ` +NaX `, ` -Infx`,
+	}
+	for _, sample := range samples {
+		lexer := json5.Scan(sample)
+		t0, err := lexer.Token()
+		hasError(t, err, "unexpected token")
+		expectToken(t, t0, json5.TypeNone)
+	}
+}
+
+func TestReadInvalidNumber(t *testing.T) {
+	samples := []string{
+		` .e8 `, ` +.e8 `, ` -. `, ` . `, ` +x `,
+	}
+	for _, sample := range samples {
+		lexer := json5.Scan(sample)
+		t0, err := lexer.Token()
+		hasError(t, err, "unexpected character")
+		expectToken(t, t0, json5.TypeNone)
+	}
+}
+
 func TestReadBool(t *testing.T) {
 	lexer := json5.Scan(` true false `)
 	t0, err := lexer.Token()
@@ -196,11 +537,77 @@ func TestReadInWhitespaces(t *testing.T) {
 	}
 }
 
-func TestReadInvalidLiteral(t *testing.T) {
+func TestReadLiteralPrefixedIdentifier(t *testing.T) {
 	lexer := json5.Scan(` falsy `)
 	t0, err := lexer.Token()
-	hasError(t, err, "unexpected token")
-	expectToken(t, t0, json5.TypeNone)
+	noError(t, err)
+	expectToken(t, t0, json5.TypeIdentifier)
+	equals(t, "falsy", t0.Raw)
+}
+
+func TestReadIdentifier(t *testing.T) {
+	samples := []string{
+		`foo`, `$foo`, `_foo`, `foo$bar_1`, `Infinityx`, `ünïcödé`, `名前`, `a\u0062c`, `\u0061bc`,
+	}
+	expectedValues := []string{
+		"foo", "$foo", "_foo", "foo$bar_1", "Infinityx", "ünïcödé", "名前", "abc", "abc",
+	}
+	for idx, sample := range samples {
+		lexer := json5.Scan(sample)
+		t0, err := lexer.Token()
+		noError(t, err)
+		expectToken(t, t0, json5.TypeIdentifier)
+		equals(t, expectedValues[idx], t0.Raw)
+		t1, err := lexer.Token()
+		noError(t, err)
+		expectToken(t, t1, json5.TypeEOF)
+	}
+}
+
+func TestReadEscapedLiteralAsIdentifier(t *testing.T) {
+	lexer := json5.Scan(`\u0074rue`)
+	t0, err := lexer.Token()
+	noError(t, err)
+	expectToken(t, t0, json5.TypeIdentifier)
+	equals(t, "true", t0.Raw)
+}
+
+func TestReadInvalidIdentifier(t *testing.T) {
+	samples := []string{
+		`\u0031abc`, `a\u002Db`, `a\x41`,
+	}
+	for _, sample := range samples {
+		lexer := json5.Scan(sample)
+		t0, err := lexer.Token()
+		if err == nil {
+			t.Fatal("Expected an error:", sample)
+		}
+		expectToken(t, t0, json5.TypeNone)
+	}
+	lexer := json5.Scan(`a\u00G1`)
+	_, err := lexer.Token()
+	hasError(t, err, "invalid hexadecimal digit: G at position 5")
+}
+
+func TestReadInUnicodeWhitespaces(t *testing.T) {
+	samples := []string{
+		"\uFEFFnull", "\u00A0null\u00A0", "\u2003null\u3000", "\u2028null\u2029", "\v\fnull\u202F",
+	}
+	for _, sample := range samples {
+		lexer := json5.Scan(sample)
+		t0, err := lexer.Token()
+		noError(t, err)
+		expectToken(t, t0, json5.TypeNull)
+		t1, err := lexer.Token()
+		noError(t, err)
+		expectToken(t, t1, json5.TypeEOF)
+	}
+}
+
+func TestReadNonWhitespaceSeparator(t *testing.T) {
+	lexer := json5.Scan("\u200Bnull")
+	_, err := lexer.Token()
+	hasError(t, err, "at position 0")
 }
 
 func TestReadSingleLineComment(t *testing.T) {
@@ -217,6 +624,21 @@ func TestReadSingleLineComment(t *testing.T) {
 	expectToken(t, t1, json5.TypeEOF)
 }
 
+func TestReadSingleLineCommentUnicodeTerminator(t *testing.T) {
+	samples := []string{
+		"// comment\u2028null", "// comment\u2029null", "// cömment\rnull",
+	}
+	for _, sample := range samples {
+		lexer := json5.Scan(sample)
+		t0, err := lexer.Token()
+		noError(t, err)
+		expectToken(t, t0, json5.TypeNull)
+		t1, err := lexer.Token()
+		noError(t, err)
+		expectToken(t, t1, json5.TypeEOF)
+	}
+}
+
 func TestReadMultipleLineComment(t *testing.T) {
 	lexer := json5.Scan(`
 	/* =================
@@ -235,6 +657,13 @@ func TestReadMultipleLineComment(t *testing.T) {
 	expectToken(t, t1, json5.TypeEOF)
 }
 
+func TestReadCommentEndingWithAsterisks(t *testing.T) {
+	lexer := json5.Scan(`/** doc **/ null`)
+	t0, err := lexer.Token()
+	noError(t, err)
+	expectToken(t, t0, json5.TypeNull)
+}
+
 func TestReadUnclosedComment(t *testing.T) {
 	lexer := json5.Scan(`
 	null /* ==== Unclosed comment ====
@@ -247,3 +676,93 @@ func TestReadUnclosedComment(t *testing.T) {
 	hasError(t, err, "unexpected end of JSON")
 	expectToken(t, t1, json5.TypeNone)
 }
+
+func TestReadTokensAtEOF(t *testing.T) {
+	samples := []string{
+		`0`, `12`, `0x1F`, `1.`, `1.5`, `1e5`, `-1E+5`, `foo`, `"foo"`, `// comment`,
+	}
+	expectedTypes := []json5.TokenType{
+		json5.TypeInteger, json5.TypeInteger, json5.TypeHexInteger, json5.TypeFloat, json5.TypeFloat,
+		json5.TypeFloat, json5.TypeFloat, json5.TypeIdentifier, json5.TypeString, json5.TypeEOF,
+	}
+	for idx, sample := range samples {
+		lexer := json5.Scan(sample)
+		t0, err := lexer.Token()
+		noError(t, err)
+		expectToken(t, t0, expectedTypes[idx])
+		t1, err := lexer.Token()
+		noError(t, err)
+		expectToken(t, t1, json5.TypeEOF)
+	}
+}
+
+func TestReadUnterminatedAtEOF(t *testing.T) {
+	samples := []string{
+		` "abc`, ` 'ab\`, ` "ab\u00`, ` "ab\x4`, ` "\ud83d\ude`, ` a\u00`, ` -Inf`, ` +Na`, ` -`, ` +`, ` .`, ` 1e`, ` 1e+`, ` 0x`, ` a\`, ` /`, ` /* abc *`,
+	}
+	expectedErrors := []string{
+		"unterminated string at position 1",
+		"unterminated escape sequence at position 4",
+		"unterminated escape sequence at position 4",
+		"unterminated escape sequence at position 4",
+		"unterminated escape sequence at position 8",
+		"unterminated escape sequence at position 2",
+		"unterminated number at position 1",
+		"unterminated number at position 1",
+		"unterminated number at position 1",
+		"unterminated number at position 1",
+		"unterminated number at position 1",
+		"unterminated number at position 1",
+		"unterminated number at position 1",
+		"unterminated number at position 1",
+		"unterminated escape sequence at position 2",
+		"unterminated comment at position 1",
+		"unterminated comment at position 1",
+	}
+	for idx, sample := range samples {
+		lexer := json5.Scan(sample)
+		t0, err := lexer.Token()
+		hasError(t, err, expectedErrors[idx])
+		expectToken(t, t0, json5.TypeNone)
+	}
+}
+
+func TestReadIncompleteExponent(t *testing.T) {
+	lexer := json5.Scan(` 1e, `)
+	t0, err := lexer.Token()
+	hasError(t, err, "unexpected character: , at position 3")
+	expectToken(t, t0, json5.TypeNone)
+}
+
+func TestTokenPositions(t *testing.T) {
+	lexer := json5.Scan("{\"ké\": [1, 'two'],\r\n  // c\n\tfoo:\r0x1F,\u2028  bar: \u00e9t\u00e9 }")
+	expected := []json5.Token{
+		{Type: json5.TypeObjectBegin, Start: 0, End: 1, Line: 1, Column: 1},
+		{Type: json5.TypeString, Start: 1, End: 6, Line: 1, Column: 2},
+		{Type: json5.TypePairSep, Start: 6, End: 7, Line: 1, Column: 6},
+		{Type: json5.TypeArrayBegin, Start: 8, End: 9, Line: 1, Column: 8},
+		{Type: json5.TypeInteger, Start: 9, End: 10, Line: 1, Column: 9},
+		{Type: json5.TypeValueSep, Start: 10, End: 11, Line: 1, Column: 10},
+		{Type: json5.TypeString, Start: 12, End: 17, Line: 1, Column: 12},
+		{Type: json5.TypeArrayEnd, Start: 17, End: 18, Line: 1, Column: 17},
+		{Type: json5.TypeValueSep, Start: 18, End: 19, Line: 1, Column: 18},
+		{Type: json5.TypeIdentifier, Start: 29, End: 32, Line: 3, Column: 2},
+		{Type: json5.TypePairSep, Start: 32, End: 33, Line: 3, Column: 5},
+		{Type: json5.TypeHexInteger, Start: 34, End: 38, Line: 4, Column: 1},
+		{Type: json5.TypeValueSep, Start: 38, End: 39, Line: 4, Column: 5},
+		{Type: json5.TypeIdentifier, Start: 44, End: 47, Line: 5, Column: 3},
+		{Type: json5.TypePairSep, Start: 47, End: 48, Line: 5, Column: 6},
+		{Type: json5.TypeIdentifier, Start: 49, End: 54, Line: 5, Column: 8},
+		{Type: json5.TypeObjectEnd, Start: 55, End: 56, Line: 5, Column: 12},
+		{Type: json5.TypeEOF, Start: 56, End: 56, Line: 5, Column: 13},
+	}
+	for _, exp := range expected {
+		tk, err := lexer.Token()
+		noError(t, err)
+		expectToken(t, tk, exp.Type)
+		equals(t, exp.Start, tk.Start)
+		equals(t, exp.End, tk.End)
+		equals(t, exp.Line, tk.Line)
+		equals(t, exp.Column, tk.Column)
+	}
+}
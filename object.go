@@ -0,0 +1,92 @@
+package json5
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Object is a JSON5 object that keeps its members in source order. A Parser
+// decodes objects as *Object when OrderedObjects is set.
+type Object struct {
+	pairs []pair
+	index map[string]int
+}
+
+// Len returns the number of members
+func (o *Object) Len() int {
+	return len(o.pairs)
+}
+
+// Keys returns the member names in order
+func (o *Object) Keys() []string {
+	keys := make([]string, len(o.pairs))
+	for i, p := range o.pairs {
+		keys[i] = p.name
+	}
+	return keys
+}
+
+// At returns the name and value of the i-th member
+func (o *Object) At(i int) (string, interface{}) {
+	p := o.pairs[i]
+	return p.name, p.value
+}
+
+// Get looks up the value of a member by name
+func (o *Object) Get(name string) (interface{}, bool) {
+	if i, ok := o.index[name]; ok {
+		return o.pairs[i].value, true
+	}
+	return nil, false
+}
+
+// Set replaces the value of an existing member in place, or appends a new
+// member at the end
+func (o *Object) Set(name string, value interface{}) {
+	if i, ok := o.index[name]; ok {
+		o.pairs[i].value = value
+		return
+	}
+	if o.index == nil {
+		o.index = make(map[string]int)
+	}
+	o.index[name] = len(o.pairs)
+	o.pairs = append(o.pairs, pair{name, value})
+}
+
+// Delete removes a member, keeping the order of the others
+func (o *Object) Delete(name string) {
+	i, ok := o.index[name]
+	if !ok {
+		return
+	}
+	delete(o.index, name)
+	o.pairs = append(o.pairs[:i], o.pairs[i+1:]...)
+	for j := i; j < len(o.pairs); j++ {
+		o.index[o.pairs[j].name] = j
+	}
+}
+
+// MarshalJSON encodes the object as JSON with its members in order
+func (o *Object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, p := range o.pairs {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(p.name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(p.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,71 @@
+package json5_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	json5 "github.com/goasm/gojson5"
+)
+
+func TestParseOrderedObjects(t *testing.T) {
+	parser := json5.Parser{}
+	parser.OrderedObjects = true
+	raw, err := parser.Parse([]byte(`{zeta: 1, alpha: [{b: 2, a: 3}], mid: {}, "": null}`))
+	noError(t, err)
+	obj, ok := raw.(*json5.Object)
+	equals(t, true, ok)
+	equals(t, 4, obj.Len())
+	equals(t, "zeta,alpha,mid,", strings.Join(obj.Keys(), ","))
+	name, value := obj.At(0)
+	equals(t, "zeta", name)
+	equals(t, int64(1), value)
+	value, ok = obj.Get("alpha")
+	equals(t, true, ok)
+	arr := value.([]interface{})
+	inner, ok := arr[0].(*json5.Object)
+	equals(t, true, ok)
+	equals(t, "b,a", strings.Join(inner.Keys(), ","))
+	value, ok = obj.Get("")
+	equals(t, true, ok)
+	equals(t, nil, value)
+	_, ok = obj.Get("missing")
+	equals(t, false, ok)
+}
+
+func TestParseOrderedObjectsDefault(t *testing.T) {
+	parser := json5.Parser{}
+	raw, err := parser.Parse([]byte(`{b: 1, a: 2}`))
+	noError(t, err)
+	_, ok := raw.(map[string]interface{})
+	equals(t, true, ok)
+}
+
+func TestObjectMarshalJSON(t *testing.T) {
+	parser := json5.Parser{}
+	parser.OrderedObjects = true
+	raw, err := parser.Parse([]byte(`{z: 'x"y', a: [1, {c: true, b: null}], m: 2.5,}`))
+	noError(t, err)
+	data, err := json.Marshal(raw)
+	noError(t, err)
+	equals(t, `{"z":"x\"y","a":[1,{"c":true,"b":null}],"m":2.5}`, string(data))
+}
+
+func TestObjectSetAndDelete(t *testing.T) {
+	obj := &json5.Object{}
+	obj.Set("b", 1)
+	obj.Set("a", 2)
+	obj.Set("c", 3)
+	obj.Set("b", 4)
+	equals(t, "b,a,c", strings.Join(obj.Keys(), ","))
+	value, _ := obj.Get("b")
+	equals(t, 4, value)
+	obj.Delete("a")
+	obj.Delete("missing")
+	equals(t, "b,c", strings.Join(obj.Keys(), ","))
+	value, _ = obj.Get("c")
+	equals(t, 3, value)
+	data, err := json.Marshal(obj)
+	noError(t, err)
+	equals(t, `{"b":4,"c":3}`, string(data))
+}
@@ -1,5 +1,12 @@
 package json5
 
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 type parserState int
 
 const (
@@ -13,30 +20,188 @@ const (
 	stateEnd
 )
 
+// expectations describe what each state is waiting for in error messages
+var expectations = [...]string{
+	stateStart:               "a value",
+	stateBeforeArrayItem:     "a value or ']'",
+	stateAfterArrayItem:      "',' or ']' after array element",
+	stateBeforePropertyName:  "a property name or '}'",
+	stateAfterPropertyName:   "':' after property name",
+	stateBeforePropertyValue: "a value after ':'",
+	stateAfterPropertyValue:  "',' or '}' after property value",
+	stateEnd:                 "end of input after the value",
+}
+
+// OverflowPolicy controls how a Parser decodes integers beyond int64
+type OverflowPolicy int
+
+// Integer overflow policies
+const (
+	// OverflowError fails with a range error
+	OverflowError OverflowPolicy = iota
+	// OverflowUint64 decodes positive integers up to 1<<64-1 as uint64 and
+	// fails for anything larger or smaller
+	OverflowUint64
+	// OverflowBigInt decodes the integer exactly as *big.Int
+	OverflowBigInt
+	// OverflowFloat64 decodes the integer as the nearest float64, losing
+	// precision beyond 53 bits
+	OverflowFloat64
+)
+
+// DuplicatePolicy controls how a Parser handles a property name that
+// appears more than once in the same object
+type DuplicatePolicy int
+
+// Duplicate key policies
+const (
+	// DuplicateLastWins keeps the value of the last occurrence
+	DuplicateLastWins DuplicatePolicy = iota
+	// DuplicateFirstWins keeps the value of the first occurrence
+	DuplicateFirstWins
+	// DuplicateError fails with the positions of both occurrences
+	DuplicateError
+	// DuplicateCollect keeps the values of all occurrences, in order, as
+	// Duplicates
+	DuplicateCollect
+)
+
+// Duplicates holds the values of a repeated key under DuplicateCollect
+type Duplicates []interface{}
+
 // Parser represents a JSON5 parser
 type Parser struct {
 	Lexer
+	// IntegerOverflow selects how integers that do not fit in int64 are
+	// decoded. Integers within range are always int64.
+	IntegerOverflow OverflowPolicy
+	// DuplicateKeys selects what happens to a property name that appears
+	// more than once in the same object.
+	DuplicateKeys DuplicatePolicy
+	// OrderedObjects decodes objects as *Object, which keeps the members in
+	// source order, instead of map[string]interface{}.
+	OrderedObjects bool
+	// Recover keeps parsing after syntax errors. Parse then reports every
+	// error found as SyntaxErrors, together with a best-effort partial value.
+	// Exceeding a limit always stops parsing.
+	Recover bool
+	// MaxInputSize limits the input length in bytes. Zero means no limit.
+	MaxInputSize int
+	// MaxDepth limits the nesting of arrays and objects. Zero means no limit.
+	MaxDepth int
+	// MaxElements limits the elements of each array and the members of each
+	// object. Zero means no limit.
+	MaxElements int
+	// MaxKeys limits the property names in the whole input. Zero means no
+	// limit.
+	MaxKeys int
+
 	state parserState
+	comma int
 	stage stateStack
 	paths nameStack
 	stack valueStack
+	errs  SyntaxErrors
+	// frames track the open arrays and objects
+	frames []frame
+	// names counts the property names read so far
+	names int
+	// validate runs the state machines without building values
+	validate bool
+}
+
+// containerMark stands in for an array or object while validating. Values
+// of a one-byte type are boxed without allocation.
+type containerMark uint8
+
+const (
+	emptyArray containerMark = iota
+	filledArray
+	emptyObject
+	filledObject
+)
+
+// frame tracks an open array or object
+type frame struct {
+	// members counts the elements or members read so far
+	members int
+	// keys holds the offsets of the names seen so far when duplicates are
+	// errors, and is nil otherwise
+	keys map[string]int
+}
+
+// pushArray opens an array, saving the current state
+func (p *Parser) pushArray() {
+	p.stage.Push(p.state)
+	p.frames = append(p.frames, frame{})
+	if p.validate {
+		p.stack.Push(emptyArray)
+		return
+	}
+	p.stack.Push(make([]interface{}, 0))
+}
+
+// pushObject opens an object, saving the current state
+func (p *Parser) pushObject() {
+	p.stage.Push(p.state)
+	var keys map[string]int
+	if p.DuplicateKeys == DuplicateError {
+		keys = make(map[string]int)
+	}
+	p.frames = append(p.frames, frame{keys: keys})
+	switch {
+	case p.validate:
+		p.stack.Push(emptyObject)
+	case p.OrderedObjects:
+		p.stack.Push(new(Object))
+	default:
+		p.stack.Push(make(map[string]interface{}))
+	}
+}
+
+// appendItem adds a value to the innermost array
+func (p *Parser) appendItem(value interface{}) {
+	top := p.stack.Size() - 1
+	switch arr := p.stack.elements[top].(type) {
+	case []interface{}:
+		p.stack.elements[top] = append(arr, value)
+	case containerMark:
+		p.stack.elements[top] = filledArray
+	}
+}
+
+// setProperty adds a member to the innermost object
+func (p *Parser) setProperty(name string, value interface{}) {
+	top := p.stack.Size() - 1
+	switch obj := p.stack.elements[top].(type) {
+	case map[string]interface{}:
+		if old, ok := obj[name]; ok {
+			value = p.resolveDuplicate(old, value)
+		}
+		obj[name] = value
+	case *Object:
+		if old, ok := obj.Get(name); ok {
+			value = p.resolveDuplicate(old, value)
+		}
+		obj.Set(name, value)
+	case containerMark:
+		p.stack.elements[top] = filledObject
+	}
 }
 
 func (p *Parser) popValue() (err error) {
 	p.state = p.stage.Pop()
+	p.frames = p.frames[:len(p.frames)-1]
 	if p.stack.Size() == 1 {
 		p.state = stateEnd
 	} else {
 		value := p.stack.Pop()
 		switch p.state {
 		case stateBeforeArrayItem:
-			arr := p.stack.Top().([]interface{})
-			p.stack.elements[p.stack.Size()-1] = append(arr, value)
+			p.appendItem(value)
 			p.state = stateAfterArrayItem
 		case stateBeforePropertyValue:
-			name := p.paths.Pop()
-			obj := p.stack.Top().(map[string]interface{})
-			obj[name] = value
+			p.setProperty(p.paths.Pop(), value)
 			p.state = stateAfterPropertyValue
 		default:
 			panic("unreachable")
@@ -45,26 +210,142 @@ func (p *Parser) popValue() (err error) {
 	return
 }
 
+// resolveDuplicate returns the value of a repeated key under the duplicate
+// key policy, given the values of the earlier and the latest occurrence
+func (p *Parser) resolveDuplicate(old, value interface{}) interface{} {
+	switch p.DuplicateKeys {
+	case DuplicateFirstWins:
+		return old
+	case DuplicateCollect:
+		if values, ok := old.(Duplicates); ok {
+			return append(values, value)
+		}
+		return Duplicates{old, value}
+	}
+	return value
+}
+
+// checkDuplicate records the offset of a property name, failing if the
+// innermost object already has it when duplicates are errors
+func (p *Parser) checkDuplicate(tk Token) error {
+	keys := p.frames[len(p.frames)-1].keys
+	if keys == nil {
+		return nil
+	}
+	if first, ok := keys[tk.Raw]; ok {
+		return duplicateKeyError(tk.Raw, p.objectPath(), first, tk.Start)
+	}
+	keys[tk.Raw] = tk.Start
+	return nil
+}
+
+// objectPath locates the innermost object from the root, e.g. $.a[2]["b c"]
+func (p *Parser) objectPath() string {
+	var sb strings.Builder
+	sb.WriteByte('$')
+	names := p.paths.elements
+	for _, v := range p.stack.elements[:p.stack.Size()-1] {
+		switch v := v.(type) {
+		case []interface{}:
+			fmt.Fprintf(&sb, "[%d]", len(v))
+		case map[string]interface{}, *Object:
+			if isIdentifierName(names[0]) {
+				sb.WriteByte('.')
+				sb.WriteString(names[0])
+			} else {
+				fmt.Fprintf(&sb, "[%q]", names[0])
+			}
+			names = names[1:]
+		}
+	}
+	return sb.String()
+}
+
+// isEmptyContainer reports whether the innermost array or object has no
+// members yet, telling a comma after '[' or '{' from a doubled one
+func (p *Parser) isEmptyContainer() bool {
+	switch v := p.stack.Top().(type) {
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	case *Object:
+		return v.Len() == 0
+	case containerMark:
+		return v == emptyArray || v == emptyObject
+	}
+	return true
+}
+
+// unexpectedToken reports a token that does not fit the current state,
+// quoting its source text along with what was expected instead
+func (p *Parser) unexpectedToken(tk Token) error {
+	expected := expectations[p.state]
+	if tk.Type == TypeEOF {
+		return unexpectedEOFError(expected, tk.Start)
+	}
+	return unexpectedTokenError(sourceText(p.str[tk.Start:tk.End]), expected, tk.Start)
+}
+
+// commaError reports a comma that is not preceded by a member
+func (p *Parser) commaError(tk Token) error {
+	if p.isEmptyContainer() {
+		return leadingCommaError(tk.Start)
+	}
+	return extraCommaError(tk.Start)
+}
+
+// checkTrailingComma rejects a closing bracket that follows a comma unless
+// the dialect allows trailing commas
+func (p *Parser) checkTrailingComma() error {
+	if !p.isEmptyContainer() && !p.Dialect.Allows(AllowTrailingCommas) {
+		return badExtensionError(AllowTrailingCommas, p.Dialect, p.comma)
+	}
+	return nil
+}
+
+// parseValue converts a primitive token after checking it against the dialect
+func (p *Parser) parseValue(tk Token) (interface{}, error) {
+	if (tk.Type == TypeInfinity || tk.Type == TypeNaN) && !p.Dialect.Allows(AllowInfinityNaN) {
+		return nil, badExtensionError(AllowInfinityNaN, p.Dialect, tk.Start)
+	}
+	var value interface{}
+	var err error
+	if p.validate {
+		// only the range of numbers is checked
+		err = checkNumber(tk.Type, p.str[tk.Start:tk.End])
+	} else {
+		value, err = parseToken(tk)
+	}
+	if err == nil {
+		return value, nil
+	}
+	if errors.Is(err, strconv.ErrRange) && tk.Type != TypeFloat {
+		if v, ok := parseBigInteger(string(p.str[tk.Start:tk.End]), p.IntegerOverflow); ok {
+			return v, nil
+		}
+	}
+	return nil, badNumberError(sourceText(p.str[tk.Start:tk.End]), err, tk.Start)
+}
+
 func (p *Parser) parseStart(tk Token) (err error) {
 	switch tk.Type {
 	case TypeArrayBegin:
-		p.stage.Push(p.state)
+		p.pushArray()
 		p.state = stateBeforeArrayItem
-		p.stack.Push(make([]interface{}, 0))
 	case TypeObjectBegin:
-		p.stage.Push(p.state)
+		p.pushObject()
 		p.state = stateBeforePropertyName
-		p.stack.Push(make(map[string]interface{}))
-	case TypeString, TypeInteger, TypeFloat, TypeFalse, TypeTrue, TypeNull:
+	case TypeString, TypeInteger, TypeHexInteger, TypeFloat, TypeInfinity, TypeNaN, TypeFalse, TypeTrue, TypeNull:
 		p.state = stateEnd
-		value, e := parseToken(tk)
+		value, e := p.parseValue(tk)
 		if e != nil {
 			err = e
 			return
 		}
 		p.stack.Push(value)
 	default:
-		err = badTokenError(tk.Raw, p.pos)
+		err = p.unexpectedToken(tk)
 	}
 	return
 }
@@ -72,26 +353,33 @@ func (p *Parser) parseStart(tk Token) (err error) {
 func (p *Parser) parseBeforeArrayItem(tk Token) (err error) {
 	switch tk.Type {
 	case TypeArrayBegin:
-		p.stage.Push(p.state)
+		p.pushArray()
 		// p.state = stateBeforeArrayItem
-		p.stack.Push(make([]interface{}, 0))
 	case TypeObjectBegin:
-		p.stage.Push(p.state)
+		p.pushObject()
 		p.state = stateBeforePropertyName
-		p.stack.Push(make(map[string]interface{}))
-	case TypeString, TypeInteger, TypeFloat, TypeFalse, TypeTrue, TypeNull:
+	case TypeString, TypeInteger, TypeHexInteger, TypeFloat, TypeInfinity, TypeNaN, TypeFalse, TypeTrue, TypeNull:
 		p.state = stateAfterArrayItem
-		value, e := parseToken(tk)
+		value, e := p.parseValue(tk)
 		if e != nil {
 			err = e
-			return
+			if !p.Recover {
+				return
+			}
+			// keep the place of the value, as for a malformed token
+			value = nil
 		}
-		arr := p.stack.Top().([]interface{})
-		p.stack.elements[p.stack.Size()-1] = append(arr, value)
+		p.appendItem(value)
 	case TypeArrayEnd:
-		err = p.popValue()
+		// either an empty array or a trailing comma
+		err = p.checkTrailingComma()
+		if e := p.popValue(); e != nil {
+			err = e
+		}
+	case TypeValueSep:
+		err = p.commaError(tk)
 	default:
-		err = badTokenError(tk.Raw, p.pos)
+		err = p.unexpectedToken(tk)
 	}
 	return
 }
@@ -100,10 +388,11 @@ func (p *Parser) parseAfterArrayItem(tk Token) (err error) {
 	switch tk.Type {
 	case TypeValueSep:
 		p.state = stateBeforeArrayItem
+		p.comma = tk.Start
 	case TypeArrayEnd:
 		err = p.popValue()
 	default:
-		err = badTokenError(tk.Raw, p.pos)
+		err = p.unexpectedToken(tk)
 	}
 	return
 }
@@ -113,10 +402,31 @@ func (p *Parser) parseBeforePropertyName(tk Token) (err error) {
 	case TypeString:
 		p.state = stateAfterPropertyName
 		p.paths.Push(tk.Raw)
+		err = p.checkDuplicate(tk)
+	case TypeIdentifier, TypeFalse, TypeTrue, TypeNull, TypeInfinity, TypeNaN:
+		if isSigned(p.str[tk.Start]) {
+			err = p.unexpectedToken(tk)
+			return
+		}
+		if !p.Dialect.Allows(AllowUnquotedKeys) {
+			// reported, but the name is still taken
+			err = badExtensionError(AllowUnquotedKeys, p.Dialect, tk.Start)
+		}
+		p.state = stateAfterPropertyName
+		p.paths.Push(tk.Raw)
+		if e := p.checkDuplicate(tk); err == nil {
+			err = e
+		}
 	case TypeObjectEnd:
-		err = p.popValue()
+		// either an empty object or a trailing comma
+		err = p.checkTrailingComma()
+		if e := p.popValue(); e != nil {
+			err = e
+		}
+	case TypeValueSep:
+		err = p.commaError(tk)
 	default:
-		err = badTokenError(tk.Raw, p.pos)
+		err = p.unexpectedToken(tk)
 	}
 	return
 }
@@ -126,7 +436,7 @@ func (p *Parser) parseAfterPropertyName(tk Token) (err error) {
 	case TypePairSep:
 		p.state = stateBeforePropertyValue
 	default:
-		err = badTokenError(tk.Raw, p.pos)
+		err = p.unexpectedToken(tk)
 	}
 	return
 }
@@ -134,25 +444,26 @@ func (p *Parser) parseAfterPropertyName(tk Token) (err error) {
 func (p *Parser) parseBeforePropertyValue(tk Token) (err error) {
 	switch tk.Type {
 	case TypeArrayBegin:
-		p.stage.Push(p.state)
+		p.pushArray()
 		p.state = stateBeforeArrayItem
-		p.stack.Push(make([]interface{}, 0))
 	case TypeObjectBegin:
-		p.stage.Push(p.state)
+		p.pushObject()
 		p.state = stateBeforePropertyName
-		p.stack.Push(make(map[string]interface{}))
-	case TypeString, TypeInteger, TypeFloat, TypeFalse, TypeTrue, TypeNull:
+	case TypeString, TypeInteger, TypeHexInteger, TypeFloat, TypeInfinity, TypeNaN, TypeFalse, TypeTrue, TypeNull:
 		p.state = stateAfterPropertyValue
-		value, e := parseToken(tk)
+		name := p.paths.Pop()
+		value, e := p.parseValue(tk)
 		if e != nil {
 			err = e
-			return
+			if !p.Recover {
+				return
+			}
+			// keep the property, as for a malformed token
+			value = nil
 		}
-		name := p.paths.Pop()
-		obj := p.stack.Top().(map[string]interface{})
-		obj[name] = value
+		p.setProperty(name, value)
 	default:
-		err = badTokenError(tk.Raw, p.pos)
+		err = p.unexpectedToken(tk)
 	}
 	return
 }
@@ -161,55 +472,164 @@ func (p *Parser) parseAfterPropertyValue(tk Token) (err error) {
 	switch tk.Type {
 	case TypeValueSep:
 		p.state = stateBeforePropertyName
+		p.comma = tk.Start
 	case TypeObjectEnd:
 		err = p.popValue()
 	default:
-		err = badTokenError(tk.Raw, p.pos)
+		err = p.unexpectedToken(tk)
 	}
 	return
 }
 
 func (p *Parser) parseEnd(tk Token) (err error) {
 	if tk.Type != TypeEOF {
-		err = badTokenError(tk.Raw, p.pos)
+		err = p.unexpectedToken(tk)
 	}
 	return
 }
 
-// Parse parses the JSON bytes
+// checkLimits fails fast on a token that would open an array or object too
+// deep, or add one element or property name too many
+func (p *Parser) checkLimits(tk Token) error {
+	switch p.state {
+	case stateStart, stateBeforeArrayItem, stateBeforePropertyValue:
+		if tk.Type != TypeArrayBegin && tk.Type != TypeObjectBegin {
+			break
+		}
+		if p.MaxDepth > 0 && len(p.frames) >= p.MaxDepth {
+			return limitError(KindDepthExceeded, "nesting depth", p.MaxDepth, tk.Start)
+		}
+	}
+	switch {
+	case p.state == stateBeforeArrayItem && startsValue(tk):
+	case p.state == stateBeforePropertyName && startsPropertyName(tk):
+		p.names++
+		if p.MaxKeys > 0 && p.names > p.MaxKeys {
+			return limitError(KindTooManyKeys, "number of keys", p.MaxKeys, tk.Start)
+		}
+	default:
+		return nil
+	}
+	f := &p.frames[len(p.frames)-1]
+	f.members++
+	if p.MaxElements > 0 && f.members > p.MaxElements {
+		return limitError(KindTooManyElements, "number of elements", p.MaxElements, tk.Start)
+	}
+	return nil
+}
+
+// parse feeds a token to the handler of the current state
+func (p *Parser) parse(tk Token) (err error) {
+	if err = p.checkLimits(tk); err != nil {
+		return
+	}
+	switch p.state {
+	case stateStart:
+		err = p.parseStart(tk)
+	case stateBeforeArrayItem:
+		err = p.parseBeforeArrayItem(tk)
+	case stateAfterArrayItem:
+		err = p.parseAfterArrayItem(tk)
+	case stateBeforePropertyName:
+		err = p.parseBeforePropertyName(tk)
+	case stateAfterPropertyName:
+		err = p.parseAfterPropertyName(tk)
+	case stateBeforePropertyValue:
+		err = p.parseBeforePropertyValue(tk)
+	case stateAfterPropertyValue:
+		err = p.parseAfterPropertyValue(tk)
+	case stateEnd:
+		err = p.parseEnd(tk)
+	}
+	return
+}
+
+// reset clears the state left by a previous Parse, keeping the options and
+// the capacity of the stacks
+func (p *Parser) reset(s []byte) {
+	p.Lexer.reset(s)
+	p.state = stateStart
+	p.comma = 0
+	p.stage.Reset()
+	p.paths.Reset()
+	p.stack.Reset()
+	p.errs = nil
+	// drop the duplicate key maps along with the frames
+	frames := p.frames[:cap(p.frames)]
+	for i := range frames {
+		frames[i] = frame{}
+	}
+	p.frames = frames[:0]
+	p.names = 0
+	p.buf.discard = p.validate
+}
+
+// fail ends parsing with err, reporting it after the errors recovered from
+func (p *Parser) fail(err error) error {
+	if len(p.errs) == 0 {
+		return err
+	}
+	return append(p.errs, err.(*SyntaxError))
+}
+
+// Parse parses the JSON bytes. A Parser may be reused for several inputs,
+// but not concurrently.
 func (p *Parser) Parse(s []byte) (value interface{}, err error) {
-	p.str = s
+	p.reset(s)
+	defer func() {
+		switch e := err.(type) {
+		case *SyntaxError:
+			if e.Line == 0 {
+				e.locate(p.str)
+			}
+		case SyntaxErrors:
+			for _, se := range e {
+				if se.Line == 0 {
+					se.locate(p.str)
+				}
+			}
+		}
+	}()
+	if p.MaxInputSize > 0 && len(s) > p.MaxInputSize {
+		err = limitError(KindInputTooLarge, "input size", p.MaxInputSize, p.MaxInputSize)
+		return
+	}
 	for {
 		tk, e := p.Token()
 		if e != nil {
-			err = e
-			return
+			if !p.Recover || isLimitError(e) {
+				err = p.fail(e)
+				return
+			}
+			var ok bool
+			if tk, ok = p.skipBadToken(e); !ok {
+				continue
+			}
 		}
-		switch p.state {
-		case stateStart:
-			err = p.parseStart(tk)
-		case stateBeforeArrayItem:
-			err = p.parseBeforeArrayItem(tk)
-		case stateAfterArrayItem:
-			err = p.parseAfterArrayItem(tk)
-		case stateBeforePropertyName:
-			err = p.parseBeforePropertyName(tk)
-		case stateAfterPropertyName:
-			err = p.parseAfterPropertyName(tk)
-		case stateBeforePropertyValue:
-			err = p.parseBeforePropertyValue(tk)
-		case stateAfterPropertyValue:
-			err = p.parseAfterPropertyValue(tk)
-		case stateEnd:
-			err = p.parseEnd(tk)
-			if err != nil {
+		if e := p.parse(tk); e != nil {
+			if !p.Recover || isLimitError(e) {
+				err = p.fail(e)
 				return
 			}
-			value = p.stack.Pop()
-			return
+			stop, limit := p.recoverFrom(tk, e)
+			if limit != nil {
+				err = p.fail(limit)
+				return
+			}
+			if stop {
+				break
+			}
 		}
-		if err != nil {
-			return
+		if tk.Type == TypeEOF {
+			break
 		}
 	}
+	// a top-level scalar that failed to convert leaves nothing to pop
+	if p.state == stateEnd && p.stack.Size() > 0 {
+		value = p.stack.Pop()
+	}
+	if len(p.errs) > 0 {
+		err = p.errs
+	}
+	return
 }
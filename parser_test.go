@@ -1,6 +1,11 @@
 package json5_test
 
 import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
 	"testing"
 
 	json5 "github.com/goasm/gojson5"
@@ -13,6 +18,28 @@ func TestParseString(t *testing.T) {
 	equals(t, "foo", val)
 }
 
+func TestParseSingleQuotedString(t *testing.T) {
+	parser := json5.Parser{}
+	raw, err := parser.Parse([]byte(` { 'foo': 'bar', "baz": ['"qux"', "'quux'"] } `))
+	noError(t, err)
+	val, ok := raw.(map[string]interface{})
+	equals(t, true, ok)
+	equals(t, "bar", val["foo"])
+	baz, ok := val["baz"].([]interface{})
+	equals(t, true, ok)
+	equals(t, `"qux"`, baz[0])
+	equals(t, `'quux'`, baz[1])
+}
+
+func TestParseWithBOM(t *testing.T) {
+	parser := json5.Parser{}
+	raw, err := parser.Parse([]byte("\uFEFF{\u00A0foo:\u2003'bar'\u2028}"))
+	noError(t, err)
+	val, ok := raw.(map[string]interface{})
+	equals(t, true, ok)
+	equals(t, "bar", val["foo"])
+}
+
 func TestParseNumber(t *testing.T) {
 	parser := json5.Parser{}
 	val, err := parser.Parse([]byte(` 100 `))
@@ -20,6 +47,101 @@ func TestParseNumber(t *testing.T) {
 	equals(t, int64(100), val)
 }
 
+func TestParseRelaxedNumbers(t *testing.T) {
+	parser := json5.Parser{}
+	raw, err := parser.Parse([]byte(` [+1, .5, 5., -.5, +.5, +5., 3.e2, .5e2, +1.5E+3] `))
+	noError(t, err)
+	val, ok := raw.([]interface{})
+	equals(t, true, ok)
+	equals(t, int64(1), val[0])
+	equals(t, 0.5, val[1])
+	equals(t, 5.0, val[2])
+	equals(t, -0.5, val[3])
+	equals(t, 0.5, val[4])
+	equals(t, 5.0, val[5])
+	equals(t, 300.0, val[6])
+	equals(t, 50.0, val[7])
+	equals(t, 1500.0, val[8])
+}
+
+func TestParseInfinityAndNaN(t *testing.T) {
+	parser := json5.Parser{}
+	raw, err := parser.Parse([]byte(` [Infinity, +Infinity, -Infinity, NaN, -NaN] `))
+	noError(t, err)
+	val, ok := raw.([]interface{})
+	equals(t, true, ok)
+	equals(t, math.Inf(1), val[0])
+	equals(t, math.Inf(1), val[1])
+	equals(t, math.Inf(-1), val[2])
+	equals(t, true, math.IsNaN(val[3].(float64)))
+	equals(t, true, math.IsNaN(val[4].(float64)))
+}
+
+func TestParseSignedKey(t *testing.T) {
+	parser := json5.Parser{}
+	_, err := parser.Parse([]byte(` { -Infinity: 1 } `))
+	hasError(t, err, "unexpected token")
+}
+
+func TestParseHexNumber(t *testing.T) {
+	parser := json5.Parser{}
+	raw, err := parser.Parse([]byte(` [0x1F, -0xff, +0xA, 0x7FFFFFFFFFFFFFFF, -0x8000000000000000] `))
+	noError(t, err)
+	val, ok := raw.([]interface{})
+	equals(t, true, ok)
+	equals(t, int64(31), val[0])
+	equals(t, int64(-255), val[1])
+	equals(t, int64(10), val[2])
+	equals(t, int64(math.MaxInt64), val[3])
+	equals(t, int64(math.MinInt64), val[4])
+}
+
+func TestParseHexNumberOverflow(t *testing.T) {
+	parser := json5.Parser{}
+	_, err := parser.Parse([]byte(` 0x10000000000000000 `))
+	hasError(t, err, "number out of range")
+}
+
+func TestParseIntegerOverflow(t *testing.T) {
+	sample := []byte(` [18446744073709551615, -9223372036854775809, 0xFFFFFFFFFFFFFFFF, 123456789012345678901234567890] `)
+	parser := json5.Parser{}
+	_, err := parser.Parse(sample)
+	hasError(t, err, "number out of range")
+
+	parser = json5.Parser{}
+	parser.IntegerOverflow = json5.OverflowUint64
+	_, err = parser.Parse(sample)
+	hasError(t, err, "number out of range")
+	parser = json5.Parser{}
+	parser.IntegerOverflow = json5.OverflowUint64
+	raw, err := parser.Parse([]byte(` [18446744073709551615, 0xFFFFFFFFFFFFFFFF, 1] `))
+	noError(t, err)
+	val := raw.([]interface{})
+	equals(t, uint64(math.MaxUint64), val[0])
+	equals(t, uint64(math.MaxUint64), val[1])
+	equals(t, int64(1), val[2])
+
+	parser = json5.Parser{}
+	parser.IntegerOverflow = json5.OverflowBigInt
+	raw, err = parser.Parse(sample)
+	noError(t, err)
+	val = raw.([]interface{})
+	equals(t, "18446744073709551615", val[0].(*big.Int).String())
+	equals(t, "-9223372036854775809", val[1].(*big.Int).String())
+	equals(t, "18446744073709551615", val[2].(*big.Int).String())
+	equals(t, "123456789012345678901234567890", val[3].(*big.Int).String())
+
+	parser = json5.Parser{}
+	parser.IntegerOverflow = json5.OverflowFloat64
+	raw, err = parser.Parse(sample)
+	noError(t, err)
+	val = raw.([]interface{})
+	equals(t, float64(1<<64), val[0])
+	equals(t, float64(-(1 << 63)), val[1])
+	equals(t, float64(1<<64), val[2])
+	equals(t, 1.2345678901234568e29, val[3])
+}
+
 func TestParseBool(t *testing.T) {
 	parser := json5.Parser{}
 	val, err := parser.Parse([]byte(` true `))
@@ -164,3 +286,618 @@ func TestParseNestedMixedObject(t *testing.T) {
 	equals(t, int64(100), quuxVal["quuz"])
 	equals(t, int64(200), quuxVal["corge"])
 }
+
+func TestParseIdentifierKeys(t *testing.T) {
+	parser := json5.Parser{}
+	raw, err := parser.Parse([]byte(` { foo: 1, $bar: 2, _baz: 3, "qux": 4 } `))
+	noError(t, err)
+	val, ok := raw.(map[string]interface{})
+	equals(t, true, ok)
+	equals(t, 4, len(val))
+	equals(t, int64(1), val["foo"])
+	equals(t, int64(2), val["$bar"])
+	equals(t, int64(3), val["_baz"])
+	equals(t, int64(4), val["qux"])
+}
+
+func TestParseReservedWordKeys(t *testing.T) {
+	parser := json5.Parser{}
+	raw, err := parser.Parse([]byte(` { true: 1, false: 2, null: 3, Infinity: 4, NaN: 5 } `))
+	noError(t, err)
+	val, ok := raw.(map[string]interface{})
+	equals(t, true, ok)
+	equals(t, 5, len(val))
+	equals(t, int64(1), val["true"])
+	equals(t, int64(2), val["false"])
+	equals(t, int64(3), val["null"])
+	equals(t, int64(4), val["Infinity"])
+	equals(t, int64(5), val["NaN"])
+}
+
+func TestParseIdentifierValue(t *testing.T) {
+	parser := json5.Parser{}
+	_, err := parser.Parse([]byte(` falsy `))
+	hasError(t, err, "unexpected token")
+}
+
+func TestParseTrailingCommas(t *testing.T) {
+	parser := json5.Parser{}
+	raw, err := parser.Parse([]byte(`
+	{
+		"foo": [1, 2, 3,],
+		"bar": { "baz": [], "qux": {}, },
+		"quux": [[1,], { "a": 1, },], // comment
+	}
+	`))
+	noError(t, err)
+	val, ok := raw.(map[string]interface{})
+	equals(t, true, ok)
+	equals(t, 3, len(val))
+	fooVal, ok := val["foo"].([]interface{})
+	equals(t, true, ok)
+	equals(t, 3, len(fooVal))
+	barVal, ok := val["bar"].(map[string]interface{})
+	equals(t, true, ok)
+	equals(t, 2, len(barVal))
+	quuxVal, ok := val["quux"].([]interface{})
+	equals(t, true, ok)
+	equals(t, 2, len(quuxVal))
+}
+
+func TestParseInvalidCommas(t *testing.T) {
+	samples := []string{
+		`[,1]`, `[,]`, `[1,,2]`, `[1,,]`, `{,}`, `{"a":1,,}`, `{ , "a": 1 }`,
+	}
+	expectedErrors := []string{
+		"unexpected leading comma at position 1",
+		"unexpected leading comma at position 1",
+		"unexpected extra comma at position 3",
+		"unexpected extra comma at position 3",
+		"unexpected leading comma at position 1",
+		"unexpected extra comma at position 7",
+		"unexpected leading comma at position 2",
+	}
+	for idx, sample := range samples {
+		parser := json5.Parser{}
+		_, err := parser.Parse([]byte(sample))
+		hasError(t, err, expectedErrors[idx])
+	}
+}
+
+func TestParseUnexpectedEOF(t *testing.T) {
+	samples := []string{
+		``, `  `, `[1, 2`, `{"a": 1,`, `{"a"`, `{"a":`,
+	}
+	for _, sample := range samples {
+		parser := json5.Parser{}
+		_, err := parser.Parse([]byte(sample))
+		hasError(t, err, "unexpected end of JSON")
+	}
+}
+
+func TestParseStrictJSON(t *testing.T) {
+	parser := json5.Parser{}
+	parser.Dialect = json5.JSON
+	raw, err := parser.Parse([]byte(`{"foo": [1, -2.5e3, "a\u0041\n"], "bar": {"baz": null}}`))
+	noError(t, err)
+	val, ok := raw.(map[string]interface{})
+	equals(t, true, ok)
+	equals(t, 2, len(val))
+}
+
+func TestParseDialectExtensions(t *testing.T) {
+	samples := []string{
+		`// c
+		1`,
+		`/* c */ 1`,
+		`[1,]`,
+		`{"a": 1,}`,
+		`{a: 1}`,
+		`{null: 1}`,
+		`'a'`,
+		`0x1F`,
+		`Infinity`,
+		`-Infinity`,
+		`NaN`,
+		`+1`,
+		`"a\
+b"`,
+		`.5`,
+		`5.`,
+		`5.e3`,
+		`"\x41"`,
+		`"\'"`,
+		"\u00A01",
+	}
+	expectedErrors := []string{
+		"comments are not allowed in strict JSON",
+		"comments are not allowed in strict JSON",
+		"trailing commas are not allowed in strict JSON",
+		"trailing commas are not allowed in strict JSON",
+		"unquoted keys are not allowed in strict JSON",
+		"unquoted keys are not allowed in strict JSON",
+		"single-quoted strings are not allowed in strict JSON",
+		"hexadecimal numbers are not allowed in strict JSON",
+		"Infinity and NaN are not allowed in strict JSON",
+		"Infinity and NaN are not allowed in strict JSON",
+		"Infinity and NaN are not allowed in strict JSON",
+		"leading plus signs are not allowed in strict JSON",
+		"multi-line strings are not allowed in strict JSON",
+		"leading or trailing decimal points are not allowed in strict JSON",
+		"leading or trailing decimal points are not allowed in strict JSON",
+		"leading or trailing decimal points are not allowed in strict JSON",
+		"JSON5 escape sequences are not allowed in strict JSON",
+		"JSON5 escape sequences are not allowed in strict JSON",
+		"JSON5 whitespace characters are not allowed in strict JSON",
+	}
+	for idx, sample := range samples {
+		parser := json5.Parser{}
+		parser.Dialect = json5.JSON
+		_, err := parser.Parse([]byte(sample))
+		hasError(t, err, expectedErrors[idx])
+
+		parser = json5.Parser{}
+		_, err = parser.Parse([]byte(sample))
+		noError(t, err)
+	}
+}
+
+func TestParseJSONC(t *testing.T) {
+	parser := json5.Parser{}
+	parser.Dialect = json5.JSONC
+	raw, err := parser.Parse([]byte(`
+	{
+		// editor settings
+		"editor.tabSize": 4, /* spaces */
+		"files.exclude": ["node_modules",],
+	}
+	`))
+	noError(t, err)
+	val, ok := raw.(map[string]interface{})
+	equals(t, true, ok)
+	equals(t, int64(4), val["editor.tabSize"])
+
+	parser = json5.Parser{}
+	parser.Dialect = json5.JSONC
+	_, err = parser.Parse([]byte(`{'a': 1}`))
+	hasError(t, err, "single-quoted strings are not allowed in JSONC")
+}
+
+func TestParseCustomDialect(t *testing.T) {
+	parser := json5.Parser{}
+	parser.Dialect = json5.JSON5 &^ json5.AllowHexNumbers
+	_, err := parser.Parse([]byte(`{a: 'b', c: 0x10}`))
+	hasError(t, err, "hexadecimal numbers are not allowed in JSON with")
+
+	parser = json5.Parser{}
+	parser.Dialect = json5.JSON | json5.AllowComments
+	_, err = parser.Parse([]byte(`/* c */ [1,]`))
+	hasError(t, err, "trailing commas are not allowed in JSON with comments")
+}
+
+func TestParseByteOrderMarkInStrictJSON(t *testing.T) {
+	parser := json5.Parser{}
+	parser.Dialect = json5.JSON
+	val, err := parser.Parse([]byte("\uFEFF[]"))
+	noError(t, err)
+	equals(t, 0, len(val.([]interface{})))
+}
+
+func TestParseErrorPositions(t *testing.T) {
+	samples := []string{
+		`[1 2]`,
+		`{"a" 1}`,
+		`{"a": 1 "b": 2}`,
+		`{"a": }`,
+		`{ 1: 2 }`,
+		`[1, 2,`,
+		`[1] [2]`,
+		`{ "caf\u00e9": 1, "b" }`,
+		`[1, "a very long string value that is cut short in messages" }`,
+	}
+	expectedErrors := []string{
+		"unexpected token: 2, expected ',' or ']' after array element at position 3",
+		"unexpected token: 1, expected ':' after property name at position 5",
+		`unexpected token: "b", expected ',' or '}' after property value at position 8`,
+		"unexpected token: }, expected a value after ':' at position 6",
+		"unexpected token: 1, expected a property name or '}' at position 2",
+		"unexpected end of JSON, expected a value or ']' at position 6",
+		"unexpected token: [, expected end of input after the value at position 4",
+		`unexpected token: }, expected ':' after property name at position 22`,
+		`unexpected token: }, expected ',' or ']' after array element at position 61`,
+	}
+	for idx, sample := range samples {
+		parser := json5.Parser{}
+		_, err := parser.Parse([]byte(sample))
+		hasError(t, err, expectedErrors[idx])
+	}
+}
+
+func TestParseErrorQuotesSource(t *testing.T) {
+	parser := json5.Parser{}
+	_, err := parser.Parse([]byte(`{"a": 1 "caf\u00e9 with a rather long key name here": 2}`))
+	hasError(t, err, `unexpected token: "caf\u00e9 with a rather long ke..., expected`)
+}
+
+func TestParseTrailingCommaPosition(t *testing.T) {
+	parser := json5.Parser{}
+	parser.Dialect = json5.JSON
+	_, err := parser.Parse([]byte(`{"a": [1, 2 , ]}`))
+	hasError(t, err, "trailing commas are not allowed in strict JSON at position 12")
+}
+
+func TestParseRecoverReportsAllErrors(t *testing.T) {
+	parser := json5.Parser{}
+	parser.Recover = true
+	raw, err := parser.Parse([]byte(`{"a": 1 "b": @, "c" 3, "d": [1 2], e: "x\1"}`))
+	errs, ok := err.(json5.SyntaxErrors)
+	equals(t, true, ok)
+	equals(t, 5, len(errs))
+	equals(t, 8, errs[0].Offset)
+	equals(t, json5.KindUnexpectedCharacter, errs[1].Kind)
+	equals(t, 13, errs[1].Offset)
+	equals(t, 20, errs[2].Offset)
+	equals(t, 31, errs[3].Offset)
+	equals(t, json5.KindInvalidEscape, errs[4].Kind)
+	equals(t, 40, errs[4].Offset)
+	equals(t, 1, errs[4].Line)
+	equals(t, 41, errs[4].Column)
+	hasError(t, err, "at position 8 (and 4 more errors)")
+	equals(t, true, errors.Is(err, json5.ErrUnexpectedCharacter))
+	val, ok := raw.(map[string]interface{})
+	equals(t, true, ok)
+	equals(t, 5, len(val))
+	equals(t, int64(1), val["a"])
+	equals(t, nil, val["b"])
+	equals(t, int64(3), val["c"])
+	arr, ok := val["d"].([]interface{})
+	equals(t, true, ok)
+	equals(t, 2, len(arr))
+	equals(t, "x", val["e"])
+}
+
+func TestParseRecoverPartialValues(t *testing.T) {
+	samples := []string{
+		`[1, 2`,
+		`{"a": [1, {"b": }`,
+		`[1, "abc`,
+		`[1, "a\x4G", 3]`,
+		`[1, ] ]`,
+		`{"a" "b"}`,
+	}
+	expectedLengths := []int{2, 1, 2, 3, 1, 1}
+	expectedErrors := []int{1, 2, 1, 1, 1, 1}
+	for idx, sample := range samples {
+		parser := json5.Parser{}
+		parser.Recover = true
+		raw, err := parser.Parse([]byte(sample))
+		errs, ok := err.(json5.SyntaxErrors)
+		equals(t, true, ok)
+		equals(t, expectedErrors[idx], len(errs))
+		switch val := raw.(type) {
+		case []interface{}:
+			equals(t, expectedLengths[idx], len(val))
+		case map[string]interface{}:
+			equals(t, expectedLengths[idx], len(val))
+		default:
+			t.Fatal("Expected a container:", raw)
+		}
+	}
+}
+
+func TestParseRecoverKeepsPlaceOfBadValues(t *testing.T) {
+	samples := []string{`[1, 1e999, 3]`, `[1, @, 3]`, `{a: 1, b: 0x1FFFFFFFFFFFFFFFF, c: 3}`}
+	for _, sample := range samples {
+		parser := json5.Parser{}
+		parser.Recover = true
+		raw, err := parser.Parse([]byte(sample))
+		errs, ok := err.(json5.SyntaxErrors)
+		equals(t, true, ok)
+		equals(t, 1, len(errs))
+		switch val := raw.(type) {
+		case []interface{}:
+			equals(t, 3, len(val))
+			equals(t, int64(1), val[0])
+			equals(t, nil, val[1])
+			equals(t, int64(3), val[2])
+		case map[string]interface{}:
+			equals(t, 3, len(val))
+			equals(t, int64(1), val["a"])
+			value, ok := val["b"]
+			equals(t, true, ok)
+			equals(t, nil, value)
+			equals(t, int64(3), val["c"])
+		default:
+			t.Fatal("Expected a container:", raw)
+		}
+	}
+}
+
+func TestParseRecoverStrictJSON(t *testing.T) {
+	parser := json5.Parser{}
+	parser.Recover = true
+	parser.Dialect = json5.JSON
+	raw, err := parser.Parse([]byte("{\n  // comment\n  a: 'x',\n  \"b\": 0x10,\n}"))
+	errs, ok := err.(json5.SyntaxErrors)
+	equals(t, true, ok)
+	equals(t, 5, len(errs))
+	for _, e := range errs {
+		equals(t, json5.KindDisallowedExtension, e.Kind)
+	}
+	equals(t, 2, errs[0].Line)
+	equals(t, 3, errs[1].Line)
+	equals(t, 3, errs[2].Line)
+	equals(t, 4, errs[3].Line)
+	equals(t, 4, errs[4].Line)
+	val, ok := raw.(map[string]interface{})
+	equals(t, true, ok)
+	equals(t, 2, len(val))
+}
+
+func TestParseRecoverValidInput(t *testing.T) {
+	parser := json5.Parser{}
+	parser.Recover = true
+	raw, err := parser.Parse([]byte(`{a: [1, 2]}`))
+	noError(t, err)
+	val, ok := raw.(map[string]interface{})
+	equals(t, true, ok)
+	equals(t, 1, len(val))
+}
+
+func TestParserReuse(t *testing.T) {
+	parser := json5.Parser{}
+	parser.Dialect = json5.JSONC
+	_, err := parser.Parse([]byte(`{"a": [1, {"b": `))
+	hasError(t, err, "unexpected end of JSON")
+	raw, err := parser.Parse([]byte(`[1, 2, // two
+	]`))
+	noError(t, err)
+	val, ok := raw.([]interface{})
+	equals(t, true, ok)
+	equals(t, 2, len(val))
+	_, err = parser.Parse([]byte("\n\n  @"))
+	e, ok := err.(*json5.SyntaxError)
+	equals(t, true, ok)
+	equals(t, 3, e.Line)
+	equals(t, 3, e.Column)
+	raw, err = parser.Parse([]byte(`"foo"`))
+	noError(t, err)
+	equals(t, "foo", raw)
+}
+
+func TestParserReuseAfterRecovery(t *testing.T) {
+	parser := json5.Parser{}
+	parser.Recover = true
+	_, err := parser.Parse([]byte(`[1 2 3]`))
+	errs, ok := err.(json5.SyntaxErrors)
+	equals(t, true, ok)
+	equals(t, 2, len(errs))
+	raw, err := parser.Parse([]byte(`{a: 1}`))
+	noError(t, err)
+	val, ok := raw.(map[string]interface{})
+	equals(t, true, ok)
+	equals(t, int64(1), val["a"])
+}
+
+func TestParseDuplicateKeys(t *testing.T) {
+	src := []byte(`{a: 1, b: {c: 2}, "a": 3, b: {c: 4}, a: [5]}`)
+	parser := json5.Parser{}
+	raw, err := parser.Parse(src)
+	noError(t, err)
+	val := raw.(map[string]interface{})
+	equals(t, 1, len(val["a"].([]interface{})))
+	equals(t, int64(4), val["b"].(map[string]interface{})["c"])
+
+	parser = json5.Parser{}
+	parser.DuplicateKeys = json5.DuplicateFirstWins
+	raw, err = parser.Parse(src)
+	noError(t, err)
+	val = raw.(map[string]interface{})
+	equals(t, int64(1), val["a"])
+	equals(t, int64(2), val["b"].(map[string]interface{})["c"])
+
+	parser = json5.Parser{}
+	parser.DuplicateKeys = json5.DuplicateCollect
+	raw, err = parser.Parse(src)
+	noError(t, err)
+	val = raw.(map[string]interface{})
+	all, ok := val["a"].(json5.Duplicates)
+	equals(t, true, ok)
+	equals(t, 3, len(all))
+	equals(t, int64(1), all[0])
+	equals(t, int64(3), all[1])
+	equals(t, 1, len(all[2].([]interface{})))
+	equals(t, 2, len(val["b"].(json5.Duplicates)))
+}
+
+func TestParseDuplicateKeysOrdered(t *testing.T) {
+	parser := json5.Parser{}
+	parser.OrderedObjects = true
+	parser.DuplicateKeys = json5.DuplicateCollect
+	raw, err := parser.Parse([]byte(`{b: 1, a: 2, b: 3}`))
+	noError(t, err)
+	obj := raw.(*json5.Object)
+	equals(t, 2, obj.Len())
+	name, value := obj.At(0)
+	equals(t, "b", name)
+	equals(t, 2, len(value.(json5.Duplicates)))
+}
+
+func TestParseDuplicateKeyError(t *testing.T) {
+	parser := json5.Parser{}
+	parser.DuplicateKeys = json5.DuplicateError
+	_, err := parser.Parse([]byte("{\"a\": [0, {\"b c\": {x: 1,\n  \"\\u0078\": 2}}]}"))
+	hasError(t, err, `duplicate key "x" in object $.a[1]["b c"] (previous at position 19) at position 27`)
+	e := expectSyntaxError(t, err)
+	equals(t, json5.KindDuplicateKey, e.Kind)
+	equals(t, 2, e.Line)
+	equals(t, 3, e.Column)
+	equals(t, true, errors.Is(err, json5.ErrDuplicateKey))
+
+	_, err = parser.Parse([]byte(`{a: {x: 1}, b: {x: 2}, a: 3}`))
+	hasError(t, err, `duplicate key "a" in object $ (previous at position 1) at position 23`)
+}
+
+func TestParseDuplicateKeyRecover(t *testing.T) {
+	parser := json5.Parser{}
+	parser.DuplicateKeys = json5.DuplicateError
+	parser.Recover = true
+	raw, err := parser.Parse([]byte(`{a: 1, a: 2, b: 3, b: 4}`))
+	errs, ok := err.(json5.SyntaxErrors)
+	equals(t, true, ok)
+	equals(t, 2, len(errs))
+	equals(t, 7, errs[0].Offset)
+	equals(t, 19, errs[1].Offset)
+	equals(t, 2, len(raw.(map[string]interface{})))
+}
+
+func TestParseLimits(t *testing.T) {
+	samples := []string{
+		`[[[1]]]`,
+		`{a: [1, 2, 3, 4], b: 5}`,
+		`"0123456789"`,
+		`{abcdefghijk: 1}`,
+		`[1234567.89e1]`,
+		`[{a: 1, b: 2}, {c: 3, d: 4}]`,
+		`[1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18]`,
+	}
+	expectedErrors := []string{
+		"nesting depth exceeds the limit of 2 at position 2",
+		"number of elements exceeds the limit of 3 at position 14",
+		"string exceeds the limit of 8 at position 0",
+		"identifier exceeds the limit of 8 at position 1",
+		"number literal exceeds the limit of 10 at position 1",
+		"number of keys exceeds the limit of 3 at position 22",
+		"input size exceeds the limit of 50 at position 50",
+	}
+	expectedKinds := []error{
+		json5.ErrDepthExceeded,
+		json5.ErrTooManyElements,
+		json5.ErrStringTooLong,
+		json5.ErrStringTooLong,
+		json5.ErrNumberTooLong,
+		json5.ErrTooManyKeys,
+		json5.ErrInputTooLarge,
+	}
+	for idx, sample := range samples {
+		parser := json5.Parser{}
+		parser.MaxDepth = 2
+		parser.MaxElements = 3
+		parser.MaxStringLength = 8
+		parser.MaxNumberLength = 10
+		parser.MaxKeys = 3
+		parser.MaxInputSize = 50
+		_, err := parser.Parse([]byte(sample))
+		hasError(t, err, expectedErrors[idx])
+		equals(t, true, errors.Is(err, expectedKinds[idx]))
+	}
+}
+
+func TestParseWithinLimits(t *testing.T) {
+	parser := json5.Parser{}
+	parser.MaxDepth = 2
+	parser.MaxElements = 3
+	parser.MaxStringLength = 8
+	parser.MaxNumberLength = 10
+	parser.MaxKeys = 3
+	parser.MaxInputSize = 50
+	samples := []string{
+		`[[1, 2, 3]]`, `"01234567"`, `'AB'`, `{abcdefgh: 1}`, `-123456.89`,
+		`{a: {b: 1, c: 2}}`, `"` + strings.Repeat("x", 8) + `"`,
+	}
+	for _, sample := range samples {
+		_, err := parser.Parse([]byte(sample))
+		noError(t, err)
+	}
+}
+
+func TestParseLimitStopsRecovery(t *testing.T) {
+	parser := json5.Parser{}
+	parser.Recover = true
+	parser.MaxDepth = 3
+	raw, err := parser.Parse([]byte(`[1 2, [[[[[[[[[[[[[[[[[[`))
+	errs, ok := err.(json5.SyntaxErrors)
+	equals(t, true, ok)
+	equals(t, 2, len(errs))
+	equals(t, json5.KindUnexpectedToken, errs[0].Kind)
+	equals(t, json5.KindDepthExceeded, errs[1].Kind)
+	equals(t, 8, errs[1].Offset)
+	equals(t, nil, raw)
+}
+
+func TestParseLimitStopsRecoveredToken(t *testing.T) {
+	samples := []string{`[1 2 3 4 5]`, `[1 [2]]`, `{"a" {}}`}
+	expectedKinds := []json5.ErrorKind{
+		json5.KindTooManyElements,
+		json5.KindDepthExceeded,
+		json5.KindDepthExceeded,
+	}
+	expectedErrors := []int{3, 2, 2}
+	expectedOffsets := []int{5, 3, 5}
+	for idx, sample := range samples {
+		parser := json5.Parser{}
+		parser.Recover = true
+		parser.MaxElements = 2
+		parser.MaxDepth = 1
+		raw, err := parser.Parse([]byte(sample))
+		errs, ok := err.(json5.SyntaxErrors)
+		equals(t, true, ok)
+		equals(t, expectedErrors[idx], len(errs))
+		last := errs[len(errs)-1]
+		equals(t, expectedKinds[idx], last.Kind)
+		equals(t, expectedOffsets[idx], last.Offset)
+		equals(t, json5.KindUnexpectedToken, errs[len(errs)-2].Kind)
+		equals(t, nil, raw)
+	}
+}
+
+func TestParseRecoverTopLevelScalar(t *testing.T) {
+	samples := []string{`1e400`, `99999999999999999999`, `0xFFFFFFFFFFFFFFFF`, `Infinity`, `-NaN`}
+	expectedKinds := []json5.ErrorKind{
+		json5.KindNumberOutOfRange,
+		json5.KindNumberOutOfRange,
+		json5.KindNumberOutOfRange,
+		json5.KindDisallowedExtension,
+		json5.KindDisallowedExtension,
+	}
+	for idx, sample := range samples {
+		parser := json5.Parser{}
+		parser.Recover = true
+		if expectedKinds[idx] == json5.KindDisallowedExtension {
+			parser.Dialect = json5.JSON
+		}
+		raw, err := parser.Parse([]byte(sample))
+		errs, ok := err.(json5.SyntaxErrors)
+		equals(t, true, ok)
+		equals(t, 1, len(errs))
+		equals(t, expectedKinds[idx], errs[0].Kind)
+		equals(t, nil, raw)
+	}
+}
+
+func TestParseNonASCIIUnexpectedCharacter(t *testing.T) {
+	parser := json5.Parser{}
+	_, err := parser.Parse([]byte(`[€]`))
+	hasError(t, err, "unexpected character: € at position 1")
+}
+
+func BenchmarkParseStrictJSON(b *testing.B) {
+	var sb strings.Builder
+	sb.WriteString("[\n")
+	for i := 0; i < 400; i++ {
+		if i > 0 {
+			sb.WriteString(",\n")
+		}
+		fmt.Fprintf(&sb, `  {"id": %d, "name": "user %d", "active": true, "score": %d.5, "tags": ["a", "b"], "parent": null}`, i, i, i)
+	}
+	sb.WriteString("\n]\n")
+	data := []byte(sb.String())
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		parser := json5.Parser{}
+		if _, err := parser.Parse(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
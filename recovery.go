@@ -0,0 +1,248 @@
+package json5
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+// skipBadToken records a lexer error and moves past the offending input. A
+// string that failed to lex is replaced with what was decoded so far, and
+// any other malformed value with null, so that the parser stays in step.
+func (p *Parser) skipBadToken(err error) (tk Token, ok bool) {
+	e, isSyntax := err.(*SyntaxError)
+	if !isSyntax {
+		e = newSyntaxError(KindUnexpectedCharacter, err.Error(), p.pos)
+	}
+	p.errs = append(p.errs, e)
+	state := p.Lexer.state
+	partial := p.buf.String()
+	p.skipAfterError(e)
+	switch state {
+	case stateDefault, stateComment, stateSingleLineComment,
+		stateMultipleLineComment, stateMultipleLineCommentEndAsterisk:
+		// nothing that looks like a value was skipped
+		return tk, false
+	case stateString, stateEscapeChar:
+		tk = Token{Type: TypeString, Raw: partial, Start: p.start, End: p.pos}
+		if p.state == stateBeforePropertyName {
+			return tk, true
+		}
+	default:
+		tk = Token{Type: TypeNull, Start: p.start, End: p.pos}
+	}
+	switch p.state {
+	case stateStart, stateBeforeArrayItem, stateBeforePropertyValue:
+		return tk, true
+	}
+	return tk, false
+}
+
+// skipAfterError moves the lexer past the input that caused err: the rest
+// of a comment or string, or else the malformed word at the error offset
+func (l *Lexer) skipAfterError(err *SyntaxError) {
+	defer l.Reset()
+	if err.Kind == KindUnexpectedEOF {
+		l.pos = len(l.str)
+		return
+	}
+	l.pos = err.Offset
+	switch l.state {
+	case stateSingleLineComment:
+		l.skipLine()
+		return
+	case stateComment, stateMultipleLineComment, stateMultipleLineCommentEndAsterisk:
+		l.skipPast([]byte("*/"))
+		return
+	case stateString, stateEscapeChar:
+		l.skipString()
+		return
+	case stateDefault:
+		if l.str[l.pos] == '/' {
+			// a comment the dialect does not allow
+			if bytes.HasPrefix(l.str[l.pos:], []byte("//")) {
+				l.skipLine()
+			} else {
+				l.skipPast([]byte("*/"))
+			}
+			return
+		}
+	case stateValue:
+		if l.str[l.pos] == '\'' {
+			// a single-quoted string the dialect does not allow
+			l.quote = '\''
+			l.pos++
+			l.skipString()
+			return
+		}
+	}
+	// skip at least one character so that the lexer makes progress
+	for i := 0; l.pos < len(l.str); i++ {
+		if (i > 0 || l.pos > l.start) && isDelimiter(l.str[l.pos]) {
+			break
+		}
+		_, size := utf8.DecodeRune(l.str[l.pos:])
+		l.pos += size
+	}
+}
+
+// skipLine moves to the next line terminator
+func (l *Lexer) skipLine() {
+	for l.pos < len(l.str) {
+		r, size := utf8.DecodeRune(l.str[l.pos:])
+		if isLineTerminator(r) {
+			return
+		}
+		l.pos += size
+	}
+}
+
+// skipPast moves behind the next occurrence of sep, or to the end of input
+func (l *Lexer) skipPast(sep []byte) {
+	if i := bytes.Index(l.str[l.pos:], sep); i >= 0 {
+		l.pos += i + len(sep)
+	} else {
+		l.pos = len(l.str)
+	}
+}
+
+// skipString moves behind the closing quote of the current string. A line
+// break ends the string early since it was most likely left unterminated.
+func (l *Lexer) skipString() {
+	for l.pos < len(l.str) {
+		switch c := l.str[l.pos]; c {
+		case l.quote:
+			l.pos++
+			return
+		case '\\':
+			l.pos += 2
+		case '\n', '\r':
+			return
+		default:
+			l.pos++
+		}
+	}
+	if l.pos > len(l.str) {
+		l.pos = len(l.str)
+	}
+}
+
+// recoverFrom records a parser error and resynchronises the state machine.
+// Missing commas and colons are assumed, missing property values drop the
+// property, and a mismatched closing bracket closes the containers up to
+// its partner. Any other offending token is skipped. It reports whether
+// parsing should stop, and hands back a limit exceeded while parsing the
+// token again so that the caller fails with it.
+func (p *Parser) recoverFrom(tk Token, err error) (stop bool, limit error) {
+	e, ok := err.(*SyntaxError)
+	if !ok {
+		e = newSyntaxError(KindUnexpectedToken, err.Error(), tk.Start)
+	}
+	if tk.Type != TypeEOF || !p.lastErrorIs(KindUnexpectedEOF) {
+		// the lexer has already reported unterminated input
+		p.errs = append(p.errs, e)
+	}
+	if e.Kind != KindUnexpectedToken && e.Kind != KindUnexpectedEOF {
+		// the token was consumed despite the error
+		return false, nil
+	}
+	switch {
+	case tk.Type == TypeEOF:
+		for p.stage.Size() > 0 {
+			p.closeContainer()
+		}
+		return true, nil
+	case p.state == stateEnd:
+		// anything after the value is ignored
+		return true, nil
+	case p.state == stateAfterArrayItem && startsValue(tk):
+		// missing ','
+		p.state = stateBeforeArrayItem
+	case p.state == stateAfterPropertyValue && startsPropertyName(tk):
+		// missing ','
+		p.state = stateBeforePropertyName
+	case p.state == stateAfterPropertyName && startsValue(tk):
+		// missing ':'
+		p.state = stateBeforePropertyValue
+	case (p.state == stateAfterPropertyName || p.state == stateBeforePropertyValue) &&
+		(tk.Type == TypeValueSep || tk.Type == TypeObjectEnd):
+		// missing ':' or value, the property is dropped
+		p.paths.Pop()
+		p.state = stateAfterPropertyValue
+	case tk.Type == TypeArrayEnd || tk.Type == TypeObjectEnd:
+		if !p.isOpen(tk.Type) {
+			return false, nil
+		}
+		p.closeContainer()
+	default:
+		return false, nil
+	}
+	// parse the token again in the recovered state
+	if err := p.parse(tk); err != nil {
+		if isLimitError(err) {
+			return true, err
+		}
+		return p.recoverFrom(tk, err)
+	}
+	return false, nil
+}
+
+// lastErrorIs reports whether the last recorded error is of the given kind
+func (p *Parser) lastErrorIs(kind ErrorKind) bool {
+	return len(p.errs) > 0 && p.errs[len(p.errs)-1].Kind == kind
+}
+
+// closeContainer ends the innermost array or object early
+func (p *Parser) closeContainer() {
+	if p.state == stateAfterPropertyName || p.state == stateBeforePropertyValue {
+		p.paths.Pop()
+	}
+	p.popValue()
+}
+
+// isOpen reports whether a closing bracket of type end has a partner among
+// the open containers
+func (p *Parser) isOpen(end TokenType) bool {
+	for _, v := range p.stack.elements {
+		switch v := v.(type) {
+		case []interface{}:
+			if end == TypeArrayEnd {
+				return true
+			}
+		case map[string]interface{}, *Object:
+			if end == TypeObjectEnd {
+				return true
+			}
+		case containerMark:
+			if (v == emptyArray || v == filledArray) == (end == TypeArrayEnd) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func startsValue(tk Token) bool {
+	switch tk.Type {
+	case TypeArrayBegin, TypeObjectBegin, TypeString, TypeInteger, TypeHexInteger, TypeFloat,
+		TypeInfinity, TypeNaN, TypeFalse, TypeTrue, TypeNull:
+		return true
+	}
+	return false
+}
+
+func startsPropertyName(tk Token) bool {
+	switch tk.Type {
+	case TypeString, TypeIdentifier, TypeFalse, TypeTrue, TypeNull, TypeInfinity, TypeNaN:
+		return true
+	}
+	return false
+}
+
+// isDelimiter reports whether c ends a malformed word during recovery
+func isDelimiter(c byte) bool {
+	switch c {
+	case '[', ']', '{', '}', ',', ':', '"', '\'', '/', ' ', '\t', '\n', '\r':
+		return true
+	}
+	return false
+}
@@ -4,9 +4,9 @@ package json5
 
 import "strconv"
 
-const _TokenType_name = "TypeNoneTypeArrayBeginTypeArrayEndTypeObjectBeginTypeObjectEndTypeValueSepTypePairSepTypeStringTypeIntegerTypeFloatTypeFalseTypeTrueTypeNullTypeEOF"
+const _TokenType_name = "TypeNoneTypeArrayBeginTypeArrayEndTypeObjectBeginTypeObjectEndTypeValueSepTypePairSepTypeStringTypeIntegerTypeHexIntegerTypeFloatTypeInfinityTypeNaNTypeFalseTypeTrueTypeNullTypeIdentifierTypeEOF"
 
-var _TokenType_index = [...]uint8{0, 8, 22, 34, 49, 62, 74, 85, 95, 106, 115, 124, 132, 140, 147}
+var _TokenType_index = [...]uint8{0, 8, 22, 34, 49, 62, 74, 85, 95, 106, 120, 129, 141, 148, 157, 165, 173, 187, 194}
 
 func (i TokenType) String() string {
 	if i < 0 || i >= TokenType(len(_TokenType_index)-1) {
@@ -2,16 +2,51 @@ package json5
 
 import (
 	"bytes"
+	"math"
+	"math/big"
 	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 // stringBuffer
 type stringBuffer struct {
 	buf bytes.Buffer
+	// discard drops everything appended when only validating
+	discard bool
 }
 
 func (sb *stringBuffer) Append(c byte) {
-	sb.buf.WriteByte(c)
+	if !sb.discard {
+		sb.buf.WriteByte(c)
+	}
+}
+
+func (sb *stringBuffer) AppendRune(r rune) {
+	if !sb.discard {
+		sb.buf.WriteRune(r)
+	}
+}
+
+func (sb *stringBuffer) AppendBytes(b []byte) {
+	if !sb.discard {
+		sb.buf.Write(b)
+	}
+}
+
+func (sb *stringBuffer) AppendString(s string) {
+	if !sb.discard {
+		sb.buf.WriteString(s)
+	}
+}
+
+func (sb *stringBuffer) Len() int {
+	return sb.buf.Len()
+}
+
+func (sb *stringBuffer) Cap() int {
+	return sb.buf.Cap()
 }
 
 func (sb *stringBuffer) Reset() {
@@ -39,6 +74,10 @@ func (s *stateStack) Push(e parserState) {
 	s.elements = append(s.elements, e)
 }
 
+func (s *stateStack) Reset() {
+	s.elements = s.elements[:0]
+}
+
 func (s *stateStack) Pop() parserState {
 	e := s.Top()
 	s.elements = s.elements[:len(s.elements)-1]
@@ -62,6 +101,15 @@ func (s *nameStack) Push(e string) {
 	s.elements = append(s.elements, e)
 }
 
+// Reset empties the stack, dropping references to the names it held
+func (s *nameStack) Reset() {
+	elements := s.elements[:cap(s.elements)]
+	for i := range elements {
+		elements[i] = ""
+	}
+	s.elements = elements[:0]
+}
+
 func (s *nameStack) Pop() string {
 	e := s.Top()
 	s.elements = s.elements[:len(s.elements)-1]
@@ -85,12 +133,51 @@ func (s *valueStack) Push(e interface{}) {
 	s.elements = append(s.elements, e)
 }
 
+// Reset empties the stack, dropping references to the values it held
+func (s *valueStack) Reset() {
+	elements := s.elements[:cap(s.elements)]
+	for i := range elements {
+		elements[i] = nil
+	}
+	s.elements = elements[:0]
+}
+
 func (s *valueStack) Pop() interface{} {
 	e := s.Top()
 	s.elements = s.elements[:len(s.elements)-1]
 	return e
 }
 
+// lineCounter
+type lineCounter struct {
+	offset int
+	line   int
+	column int
+}
+
+// Advance moves the counter forward to offset, counting LF, CR, CRLF, U+2028
+// and U+2029 as line breaks and every other rune as a column
+func (lc *lineCounter) Advance(str []byte, offset int) {
+	for lc.offset < offset {
+		if c := str[lc.offset]; c < utf8.RuneSelf && c != '\n' && c != '\r' {
+			lc.column++
+			lc.offset++
+			continue
+		}
+		r, size := utf8.DecodeRune(str[lc.offset:])
+		switch {
+		case r == '\n' && lc.offset > 0 && str[lc.offset-1] == '\r':
+			// second half of CRLF
+		case isLineTerminator(r):
+			lc.line++
+			lc.column = 0
+		default:
+			lc.column++
+		}
+		lc.offset += size
+	}
+}
+
 // pair
 type pair struct {
 	name  string
@@ -112,12 +199,177 @@ func expectLiteral(l *Lexer, expected string) bool {
 	return i == len(expected)
 }
 
+func expectHexDigits(l *Lexer, n int) (value rune, ok bool) {
+	for i := 0; i < n; i++ {
+		if l.pos >= len(l.str) {
+			return 0, false
+		}
+		d, valid := hexDigitValue(l.str[l.pos])
+		if !valid {
+			return 0, false
+		}
+		value = value<<4 | d
+		l.pos++
+	}
+	return value, true
+}
+
+func hexDigitValue(c byte) (rune, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return rune(c - '0'), true
+	case 'a' <= c && c <= 'f':
+		return rune(c - 'a' + 10), true
+	case 'A' <= c && c <= 'F':
+		return rune(c - 'A' + 10), true
+	}
+	return 0, false
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isHexDigit(c byte) bool {
+	_, ok := hexDigitValue(c)
+	return ok
+}
+
+// decodeRune decodes the character starting at the current position, c
+// being its first byte
+func decodeRune(l *Lexer, c byte) (rune, int) {
+	if c < utf8.RuneSelf {
+		return rune(c), 1
+	}
+	return utf8.DecodeRune(l.str[l.pos:])
+}
+
+// isLineTerminator reports whether r is an ECMAScript LineTerminator
+func isLineTerminator(r rune) bool {
+	switch r {
+	case '\n', '\r', '\u2028', '\u2029':
+		return true
+	}
+	return false
+}
+
+// isWhitespace reports whether r is JSON5 WhiteSpace or a LineTerminator
+func isWhitespace(r rune) bool {
+	if r < utf8.RuneSelf {
+		switch r {
+		case '\t', '\n', '\v', '\f', '\r', ' ':
+			return true
+		}
+		return false
+	}
+	switch r {
+	case '\t', '\v', '\f', ' ', '\u00A0', '\uFEFF':
+		return true
+	}
+	return isLineTerminator(r) || unicode.Is(unicode.Zs, r)
+}
+
+// isIdentifierStart reports whether r may begin an ECMAScript IdentifierName
+func isIdentifierStart(r rune) bool {
+	if r < utf8.RuneSelf {
+		return 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || r == '$' || r == '_'
+	}
+	return unicode.In(r, unicode.L, unicode.Nl, unicode.Other_ID_Start) &&
+		!unicode.In(r, unicode.Pattern_Syntax, unicode.Pattern_White_Space)
+}
+
+// isIdentifierPart reports whether r may continue an ECMAScript IdentifierName
+func isIdentifierPart(r rune) bool {
+	if r < utf8.RuneSelf {
+		return isIdentifierStart(r) || '0' <= r && r <= '9'
+	}
+	if isIdentifierStart(r) || r == '\u200C' || r == '\u200D' {
+		return true
+	}
+	return unicode.In(r, unicode.Mn, unicode.Mc, unicode.Nd, unicode.Pc, unicode.Other_ID_Continue) &&
+		!unicode.In(r, unicode.Pattern_Syntax, unicode.Pattern_White_Space)
+}
+
+// sourceText shortens the source text of a token for error messages
+func sourceText(src []byte) string {
+	const maxLen = 32
+	if len(src) <= maxLen {
+		return string(src)
+	}
+	n := maxLen
+	for n > 0 && !utf8.RuneStart(src[n]) {
+		n--
+	}
+	return string(src[:n]) + "..."
+}
+
 func parseInteger(s string) (int64, error) {
 	return strconv.ParseInt(s, 10, 64)
 }
 
+// parseHexInteger parses a hexadecimal literal such as 0x1F, -0xff or +0xA
+func parseHexInteger(s string) (int64, error) {
+	return strconv.ParseInt(s, 0, 64)
+}
+
+// parseBigInteger decodes an integer literal that overflows int64 according
+// to the policy, reporting false when the policy rejects it
+func parseBigInteger(s string, policy OverflowPolicy) (interface{}, bool) {
+	if policy == OverflowError {
+		return nil, false
+	}
+	n, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		return nil, false
+	}
+	switch policy {
+	case OverflowUint64:
+		if n.IsUint64() {
+			return n.Uint64(), true
+		}
+		return nil, false
+	case OverflowBigInt:
+		return n, true
+	case OverflowFloat64:
+		f, _ := new(big.Float).SetInt(n).Float64()
+		return f, true
+	}
+	return nil, false
+}
+
+// parseFloat parses a decimal literal, which may carry an explicit plus sign
+// and omit the digits on either side of the decimal point, e.g. +.5 or 5.e3
 func parseFloat(s string) (float64, error) {
-	return strconv.ParseFloat(s, 64)
+	return strconv.ParseFloat(strings.TrimPrefix(s, "+"), 64)
+}
+
+// isIdentifierName reports whether name can be written without quotes
+func isIdentifierName(name string) bool {
+	for i, r := range name {
+		if !isIdentifierPart(r) || (i == 0 && !isIdentifierStart(r)) {
+			return false
+		}
+	}
+	return name != ""
+}
+
+// isSigned reports whether c is an explicit sign of a numeric literal
+func isSigned(c byte) bool {
+	return c == '+' || c == '-'
+}
+
+// checkNumber reports whether the source of a numeric token is in range like
+// parseToken does, without boxing the value
+func checkNumber(typ TokenType, src []byte) (err error) {
+	switch typ {
+	case TypeInteger:
+		_, err = parseInteger(string(src))
+	case TypeHexInteger:
+		_, err = parseHexInteger(string(src))
+	case TypeFloat:
+		_, err = parseFloat(string(src))
+	}
+	return
 }
 
 func parseToken(tk Token) (interface{}, error) {
@@ -126,8 +378,17 @@ func parseToken(tk Token) (interface{}, error) {
 		return tk.Raw, nil
 	case TypeInteger:
 		return parseInteger(tk.Raw)
+	case TypeHexInteger:
+		return parseHexInteger(tk.Raw)
 	case TypeFloat:
 		return parseFloat(tk.Raw)
+	case TypeInfinity:
+		if tk.Raw[0] == '-' {
+			return math.Inf(-1), nil
+		}
+		return math.Inf(1), nil
+	case TypeNaN:
+		return math.NaN(), nil
 	case TypeFalse:
 		return false, nil
 	case TypeTrue:
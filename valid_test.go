@@ -0,0 +1,69 @@
+package json5_test
+
+import (
+	"testing"
+
+	json5 "github.com/goasm/gojson5"
+)
+
+func TestValid(t *testing.T) {
+	samples := []string{
+		`{}`, `[]`, `null`, `'foo'`, `-Infinity`, `0x1F`,
+		`{foo: [1, 2, {bar: "bazé\n"},], 'qux': .5e3, "": +NaN}`,
+		"// comment\n[1, /* two */ 2]",
+		`{ab: true}`,
+	}
+	for _, sample := range samples {
+		equals(t, true, json5.Valid([]byte(sample)))
+		equals(t, nil, json5.Validate([]byte(sample)))
+	}
+}
+
+func TestValidateReportsParseErrors(t *testing.T) {
+	samples := []string{
+		``, `[1 2]`, `{"a": 1,,}`, `{-Infinity: 1}`, `{a: }`, `[1, 2`, `"abc`,
+		`"\1"`, `99999999999999999999`, `1e400`, `[1] 2`, `{0a: 1}`, `@`,
+	}
+	for _, sample := range samples {
+		equals(t, false, json5.Valid([]byte(sample)))
+		e := expectSyntaxError(t, json5.Validate([]byte(sample)))
+		parser := json5.Parser{}
+		_, err := parser.Parse([]byte(sample))
+		equals(t, err.Error(), e.Error())
+		equals(t, expectSyntaxError(t, err).Line, e.Line)
+	}
+}
+
+func BenchmarkValid(b *testing.B) {
+	data := []byte(`{
+		// a small configuration file
+		name: 'gojson5',
+		version: [1, 2, 3],
+		features: {comments: true, trailingCommas: true, hex: 0xFF, ratio: .5,},
+		description: "JSON5 lexer and parser é written in Go",
+	}`)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		if !json5.Valid(data) {
+			b.Fatal("Expected valid input")
+		}
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	data := []byte(`{
+		// a small configuration file
+		name: 'gojson5',
+		version: [1, 2, 3],
+		features: {comments: true, trailingCommas: true, hex: 0xFF, ratio: .5,},
+		description: "JSON5 lexer and parser é written in Go",
+	}`)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		if _, err := json5.Parse(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}